@@ -0,0 +1,53 @@
+terraform {
+  required_providers {
+    densify = {
+      source = "densify.com/provider/densify"
+    }
+  }
+}
+
+provider "densify" {
+  tech_platform  = "aws" # or can be passed in as env variable: DENSIFY_TECH_PLATFORM
+  account_number = var.account_number
+  system_name    = var.name
+}
+
+data "densify_aws_asg" "optimization" {
+  name = var.name
+}
+
+provider "aws" {
+  region = "us-east-2"
+}
+
+resource "aws_launch_template" "web" {
+  name_prefix   = var.name
+  image_id      = "ami-00eeedc4036573771" # Ubuntu 22.04 LTS
+  instance_type = data.densify_aws_asg.optimization.recommended_type
+}
+
+resource "aws_autoscaling_group" "web" {
+  name               = var.name
+  availability_zones = ["us-east-2a"]
+
+  # self-optimizing group sizes from Densify, in the capacity units of the weighted overrides below
+  min_size         = data.densify_aws_asg.optimization.recommended_weighted_min_size
+  max_size         = data.densify_aws_asg.optimization.recommended_weighted_max_size
+  desired_capacity = data.densify_aws_asg.optimization.recommended_weighted_desired_capacity
+
+  mixed_instances_policy {
+    launch_template {
+      launch_template_specification {
+        launch_template_id = aws_launch_template.web.id
+      }
+
+      dynamic "override" {
+        for_each = data.densify_aws_asg.optimization.mixed_instances_overrides
+        content {
+          instance_type     = override.value.instance_type
+          weighted_capacity = override.value.weighted_capacity
+        }
+      }
+    }
+  }
+}
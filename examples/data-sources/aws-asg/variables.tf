@@ -0,0 +1,7 @@
+variable "name" {
+  default = "<asg_name>"
+}
+
+variable "account_number" {
+  default = "<account_number>"
+}
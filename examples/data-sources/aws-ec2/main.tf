@@ -14,7 +14,13 @@ provider "densify" {
   # continue_if_error = true
   fallback_instance_type = "m4.large" // backup/fallback instance type until there is a recommendation
 }
-data "densify_cloud" "optimization" {}
+data "densify_cloud" "optimization" {
+  # apply low effort recommendations automatically, otherwise wait for approval in Densify
+  auto_approve {
+    max_effort  = "low"
+    min_savings = 20
+  }
+}
 
 provider "aws" {
   region = "us-east-2"
@@ -26,7 +32,7 @@ resource "aws_instance" "create" {
   # instance_type = "m4.large"
 
   # new self-optimizing instance type from Densify
-  instance_type = data.densify_cloud.optimization.recommended_type
+  instance_type = data.densify_cloud.optimization.approved_type
 
 
   ami = "ami-00eeedc4036573771" # Ubuntu 22.04 LTS
@@ -0,0 +1,33 @@
+terraform {
+  required_providers {
+    densify = {
+      source = "densify.com/provider/densify"
+    }
+  }
+}
+
+provider "densify" {
+  tech_platform  = "aws" # or can be passed in as env variable: DENSIFY_TECH_PLATFORM
+  account_number = var.account_number
+  system_name    = var.name
+}
+
+data "densify_database" "optimization" {
+  name = var.name
+}
+
+provider "aws" {
+  region = "us-east-2"
+}
+
+resource "aws_db_instance" "db" {
+  identifier        = var.name
+  engine            = "postgres"
+  username          = "dbadmin"
+  password          = var.db_password
+  allocated_storage = data.densify_database.optimization.recommended_allocated_storage_gb
+
+  # self-optimizing instance class and storage from Densify
+  instance_class = data.densify_database.optimization.recommended_instance_class
+  storage_type   = data.densify_database.optimization.recommended_storage_type
+}
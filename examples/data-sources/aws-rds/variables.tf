@@ -0,0 +1,11 @@
+variable "name" {
+  default = "<db_identifier>"
+}
+
+variable "account_number" {
+  default = "<account_number>"
+}
+
+variable "db_password" {
+  sensitive = true
+}
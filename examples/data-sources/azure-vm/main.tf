@@ -0,0 +1,23 @@
+terraform {
+  required_providers {
+    densify = {
+      source = "densify.com/provider/densify"
+    }
+  }
+}
+
+# credentials can be passed in as environment variables, DENSIFY_INSTANCE, DENSIFY_USERNAME, DENSIFY_PASSWORD
+provider "densify" {
+  tech_platform  = "azure"
+  account_number = var.subscription_id
+  system_name    = var.vm_name
+}
+
+data "densify_azure_vm" "optimization" {
+  resource_group = var.resource_group
+  vm_name        = var.vm_name
+}
+
+output "data_azure_vm" {
+  value = data.densify_azure_vm.optimization
+}
@@ -0,0 +1,11 @@
+variable "subscription_id" {
+  default = "<subscription_id>"
+}
+
+variable "resource_group" {
+  default = "<resource_group>"
+}
+
+variable "vm_name" {
+  default = "<vm_name>"
+}
@@ -0,0 +1,38 @@
+terraform {
+  required_providers {
+    densify = {
+      source = "densify.com/provider/densify"
+    }
+  }
+}
+
+provider "densify" {
+  tech_platform  = "aws" # or can be passed in as env variable: DENSIFY_TECH_PLATFORM
+  account_number = var.account_number
+  system_name    = var.node_group
+}
+
+data "densify_k8s_node_group" "optimization" {
+  cluster    = var.cluster
+  node_group = var.node_group
+}
+
+provider "aws" {
+  region = "us-east-2"
+}
+
+resource "aws_eks_node_group" "workers" {
+  cluster_name    = var.cluster
+  node_group_name = var.node_group
+  node_role_arn   = var.node_role_arn
+  subnet_ids      = var.subnet_ids
+
+  # self-optimizing node type and counts from Densify
+  instance_types = [data.densify_k8s_node_group.optimization.recommended_type]
+
+  scaling_config {
+    min_size     = data.densify_k8s_node_group.optimization.recommended_min_nodes
+    max_size     = data.densify_k8s_node_group.optimization.recommended_max_nodes
+    desired_size = data.densify_k8s_node_group.optimization.recommended_desired_nodes
+  }
+}
@@ -0,0 +1,20 @@
+variable "account_number" {
+  default = "<account_number>"
+}
+
+variable "cluster" {
+  default = "<cluster_name>"
+}
+
+variable "node_group" {
+  default = "<node_group_name>"
+}
+
+variable "node_role_arn" {
+  default = "<node_role_arn>"
+}
+
+variable "subnet_ids" {
+  type    = list(string)
+  default = []
+}
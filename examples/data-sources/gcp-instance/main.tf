@@ -0,0 +1,27 @@
+terraform {
+  required_providers {
+    densify = {
+      source = "densify.com/provider/densify"
+    }
+  }
+}
+
+# credentials can be passed in as environment variables, DENSIFY_INSTANCE, DENSIFY_USERNAME, DENSIFY_PASSWORD
+provider "densify" {
+  tech_platform  = "gcp"
+  account_number = var.project
+  system_name    = var.instance_name
+}
+
+data "densify_gcp_instance" "optimization" {
+  project       = var.project
+  zone          = var.zone
+  instance_name = var.instance_name
+}
+
+output "data_gcp_instance" {
+  value = data.densify_gcp_instance.optimization
+}
+
+# custom machine types can also be rebuilt from the decomposed values, ex. with the google provider:
+# machine_type = data.densify_gcp_instance.optimization.recommended_is_custom ? format("custom-%d-%d", data.densify_gcp_instance.optimization.recommended_vcpus, data.densify_gcp_instance.optimization.recommended_memory_mb) : data.densify_gcp_instance.optimization.recommended_machine_type
@@ -0,0 +1,11 @@
+variable "project" {
+  default = "<project_id>"
+}
+
+variable "zone" {
+  default = "<zone>"
+}
+
+variable "instance_name" {
+  default = "<instance_name>"
+}
@@ -47,27 +47,21 @@ resource "kubernetes_deployment" "den-web" {
           }
 
           resources {
-            requests = {
-              # original resource settings
-              # cpu     = "1200m"
-              # memory  = "4000Mi"
+            # original resource settings
+            # requests = {
+            #   cpu    = "1200m"
+            #   memory = "4000Mi"
+            # }
+            # limits = {
+            #   cpu    = "4000m"
+            #   memory = "5120Mi"
+            # }
 
-              # utilize Densify recommendations instead
-              cpu    = data.densify_container.optimized.containers.my-container.rec_cpu_req
-              memory = data.densify_container.optimized.containers.my-container.rec_mem_req
-            }
-            limits = {
-              # original resource settings
-              # cpu     = "4000m"
-              # memory  = "5120Mi"
-
-              # utilize Densify recommendations instead
-              cpu    = data.densify_container.optimized.containers.my-container.rec_cpu_lim
-              memory = data.densify_container.optimized.containers.my-container.rec_mem_lim
-            }
+            # utilize Densify recommendations instead
+            requests = data.densify_container.optimized.containers["my-container"].resources_requests
+            limits   = data.densify_container.optimized.containers["my-container"].resources_limits
           }
 
-
           command = ["sleep"]
           args    = ["infinity"]
         }
@@ -21,7 +21,19 @@ provider "densify" {
 
 data "densify_container" "reco" {}
 
+# when pod names are generated, look up the controller by labels or by the pod's owner reference instead
+# data "densify_container" "reco" {
+#   match_labels = {
+#     app = "web"
+#   }
+#   # or: resolves the web-7d9c8f ReplicaSet to the web Deployment
+#   owner_reference = {
+#     kind = "ReplicaSet"
+#     name = "web-7d9c8f"
+#   }
+# }
+
 output "data_container" {
   value = data.densify_container.reco
-  # value = data.densify_container.reco.containers.<container-name>.rec_cpu_req
+  # value = data.densify_container.reco.containers["<container-name>"].recommended_cpu_request
 }
@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    densify = {
+      source = "densify.com/provider/densify"
+    }
+    local = {
+      source = "hashicorp/local"
+    }
+  }
+}
+
+# credentials can be passed in as environment variables, DENSIFY_INSTANCE, DENSIFY_USERNAME, DENSIFY_PASSWORD
+provider "densify" {
+  tech_platform = "kubernetes"
+  cluster       = var.cluster
+}
+
+# patches for every controller of the namespace
+data "densify_k8s_resource_patch" "optimized" {
+  namespace = var.namespace
+}
+
+# write one kustomize patch per controller into the GitOps repository
+resource "local_file" "patch" {
+  for_each = data.densify_k8s_resource_patch.optimized.patches
+
+  filename = "${var.kustomize_dir}/densify-${lower(each.value.kind)}-${each.value.name}.yaml"
+  content  = each.value.yaml
+}
@@ -0,0 +1,11 @@
+variable "cluster" {
+  default = "<cluster_name>"
+}
+
+variable "namespace" {
+  default = "<namespace>"
+}
+
+variable "kustomize_dir" {
+  default = "./overlays/densify"
+}
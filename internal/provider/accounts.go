@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/joelpereira/densify-api-client-go"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// accountCache keeps the sources on which GetAccountOrCluster succeeded, per source name (the Densify instance and
+// user), technology platform and account name or number (or Kubernetes cluster), so that it is only called once per
+// account no matter how many data sources look it up. An account looked up by name is cached under the account
+// number it resolves to as well, so that later lookups by number reuse it. Each provider instance has its own cache, so provider aliases never share clients.
+// The zero value is an empty cache.
+type accountCache struct {
+	mu       sync.Mutex
+	accounts map[string]resolvedAccount
+}
+
+// resolvedAccount is a source whose account is resolved, along with the account returned by LookupAccount.
+type resolvedAccount struct {
+	source  RecommendationSource
+	account string
+}
+
+// accountNumberSchemaAttribute defines the account_number argument of the cloud data sources.
+func accountNumberSchemaAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:    true,
+		Description: "Densify account number (ex. the AWS account ID) to look up the recommendation in. Defaults to the provider account_number.",
+	}
+}
+
+// accountNameSchemaAttribute defines the account_name argument of the cloud data sources.
+func accountNameSchemaAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:    true,
+		Description: "Densify account name to look up the recommendation in, instead of account_number. Defaults to the provider account_name.",
+	}
+}
+
+// setAccount overrides the provider account of a query with the account_number or account_name argument
+// of a data source, when set.
+func setAccount(query *densify.DensifyAPIQuery, accountNumber types.String, accountName types.String) {
+	if !accountNumber.IsNull() && accountNumber.ValueString() != "" {
+		query.AccountNumber = accountNumber.ValueString()
+		query.AccountName = ""
+	} else if !accountName.IsNull() && accountName.ValueString() != "" {
+		query.AccountName = accountName.ValueString()
+		query.AccountNumber = ""
+	}
+}
+
+// describeAccount says which source supplied the account of a cloud data source, to be appended to a diagnostic detail.
+func (densifysettings *DensifySettings) describeAccount(accountNumber types.String, accountName types.String) string {
+	if !accountNumber.IsNull() && accountNumber.ValueString() != "" {
+		return fmt.Sprintf("\n\nData source settings:\n- account_number %q is set on the data source", accountNumber.ValueString())
+	}
+	if !accountName.IsNull() && accountName.ValueString() != "" {
+		return fmt.Sprintf("\n\nData source settings:\n- account_name %q is set on the data source", accountName.ValueString())
+	}
+	return densifysettings.explain("account_number", "account_name")
+}
+
+// resolve returns a copy of the client whose account is resolved, along with the account returned by
+// GetAccountOrCluster. It is called the first time an account is looked up; later lookups of the same account
+// reuse the resolved client state with the query of the new client. The client is returned as is on error.
+func (c *accountCache) resolve(ctx context.Context, client RecommendationSource) (RecommendationSource, string, error) {
+	recordQuery(ctx, client.Query())
+	ctx, span := startSpan(ctx, "densify.resolve_account")
+	defer span.End()
+
+	query := client.Query()
+	var key string
+	switch {
+	case query.AnalysisTechnology == k8sPlatform:
+		if query.K8sCluster == "" {
+			return client, "", fmt.Errorf("no Kubernetes cluster is selected. Set cluster on the provider")
+		}
+		key = accountKey(client, "cluster", query.K8sCluster)
+	case query.AccountNumber != "":
+		key = accountKey(client, "number", query.AccountNumber)
+	case query.AccountName != "":
+		key = accountKey(client, "name", query.AccountName)
+	default:
+		return client, "", fmt.Errorf("no account is selected. Set account_number or account_name on the provider or the data source")
+	}
+
+	c.mu.Lock()
+	resolved, ok := c.accounts[key]
+	c.mu.Unlock()
+	if ok {
+		recordResult(ctx, resultCache)
+		return client.WithResolvedAccount(resolved.source), resolved.account, nil
+	}
+
+	recordResult(ctx, sourceResult(client))
+	account, err := client.LookupAccount()
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return client, "", err
+	}
+	c.mu.Lock()
+	if c.accounts == nil {
+		c.accounts = map[string]resolvedAccount{}
+	}
+	c.accounts[key] = resolvedAccount{source: client, account: account}
+	if query.AnalysisTechnology != k8sPlatform && account != "" {
+		// the same account, looked up by number.
+		c.accounts[accountKey(client, "number", account)] = resolvedAccount{source: client, account: account}
+	}
+	c.mu.Unlock()
+	return client, account, nil
+}
+
+// accountKey returns the cache key of an account (or cluster) of a source, by kind of lookup: name, number or cluster.
+func accountKey(client RecommendationSource, kind string, value string) string {
+	return client.Name() + "|" + client.Query().AnalysisTechnology + "|" + kind + ":" + value
+}
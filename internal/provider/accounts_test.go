@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+// accountSource is a RecommendationSource whose lookups rely on LookupAccount, like the Densify API client.
+type accountSource struct {
+	name     string
+	query    *densify.DensifyAPIQuery
+	lookups  *int
+	resolved string
+}
+
+func (s *accountSource) Name() string                    { return s.name }
+func (s *accountSource) Query() *densify.DensifyAPIQuery { return s.query }
+func (s *accountSource) WithQuery(configure func(query *densify.DensifyAPIQuery)) (RecommendationSource, error) {
+	query := *s.query
+	configure(&query)
+	return &accountSource{name: s.name, query: &query, lookups: s.lookups, resolved: s.resolved}, nil
+}
+func (s *accountSource) WithContext(_ context.Context) RecommendationSource { return s }
+
+func (s *accountSource) LookupAccount() (string, error) {
+	*s.lookups++
+	if s.query.AccountNumber == "" && s.query.AccountName != "prod" && s.query.K8sCluster == "" {
+		return "", fmt.Errorf("account %q not found", s.query.AccountName)
+	}
+	s.resolved = "internal-id-42"
+	return s.resolved, nil
+}
+
+func (s *accountSource) WithResolvedAccount(resolved RecommendationSource) RecommendationSource {
+	r, ok := resolved.(*accountSource)
+	if !ok {
+		return s
+	}
+	return &accountSource{name: s.name, query: s.query, lookups: s.lookups, resolved: r.resolved}
+}
+
+func (s *accountSource) Recommendation() (*densify.DensifyRecommendation, error) {
+	if s.resolved == "" {
+		return nil, fmt.Errorf("GetAccountOrCluster was not called")
+	}
+	return &densify.DensifyRecommendation{Name: s.query.SystemName}, nil
+}
+
+func (s *accountSource) Recommendations() ([]densify.DensifyRecommendation, error) {
+	if s.resolved == "" {
+		return nil, fmt.Errorf("GetAccountOrCluster was not called")
+	}
+	return nil, nil
+}
+
+func (s *accountSource) ApprovedType(reco *densify.DensifyRecommendation) string {
+	return reco.ApprovedType
+}
+
+func TestAccountCacheResolve(t *testing.T) {
+	ctx := context.Background()
+	cache := &accountCache{}
+	lookups := 0
+	source := &accountSource{name: "https://densify", query: &densify.DensifyAPIQuery{AnalysisTechnology: "aws"}, lookups: &lookups}
+
+	for i, system := range []string{"web-01", "web-02"} {
+		client, err := source.WithQuery(func(query *densify.DensifyAPIQuery) {
+			query.AccountName = "prod"
+			query.SystemName = system
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		client, account, err := cache.resolve(ctx, client)
+		if err != nil {
+			t.Fatalf("lookup %d: resolve() error = %v", i, err)
+		}
+		if account != "internal-id-42" {
+			t.Errorf("lookup %d: resolve() account = %q, want %q", i, account, "internal-id-42")
+		}
+		if client.Query().AccountNumber != "" || client.Query().AccountName != "prod" {
+			t.Errorf("lookup %d: resolve() changed the query account to %q/%q", i, client.Query().AccountNumber, client.Query().AccountName)
+		}
+		reco, err := client.Recommendation()
+		if err != nil {
+			t.Fatalf("lookup %d: Recommendation() error = %v", i, err)
+		}
+		if reco.Name != system {
+			t.Errorf("lookup %d: Recommendation() name = %q, want %q", i, reco.Name, system)
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("LookupAccount() calls = %d, want 1", lookups)
+	}
+
+	// the same account name on another platform is another account.
+	client, _ := source.WithQuery(func(query *densify.DensifyAPIQuery) {
+		query.AnalysisTechnology = "azure"
+		query.AccountName = "prod"
+	})
+	if _, _, err := cache.resolve(ctx, client); err != nil {
+		t.Fatalf("resolve() error = %v", err)
+	}
+	if lookups != 2 {
+		t.Errorf("LookupAccount() calls = %d, want 2", lookups)
+	}
+
+	// failed lookups are not cached, and return the client so its query can still be read.
+	for i := 0; i < 2; i++ {
+		client, _ := source.WithQuery(func(query *densify.DensifyAPIQuery) {
+			query.AccountName = "dev"
+		})
+		resolved, _, err := cache.resolve(ctx, client)
+		if err == nil {
+			t.Fatal("resolve() error = nil, want an unknown account error")
+		}
+		if resolved != client {
+			t.Errorf("resolve() = %v, want the client on error", resolved)
+		}
+	}
+	if lookups != 4 {
+		t.Errorf("LookupAccount() calls = %d, want 4", lookups)
+	}
+
+	// another user of the same instance resolves its own account.
+	other := &accountSource{name: "admin@https://densify", query: &densify.DensifyAPIQuery{AnalysisTechnology: "aws", AccountName: "prod"}, lookups: &lookups}
+	if _, _, err := cache.resolve(ctx, other); err != nil {
+		t.Fatalf("resolve() error = %v", err)
+	}
+	if lookups != 5 {
+		t.Errorf("LookupAccount() calls = %d, want 5", lookups)
+	}
+
+	// the account resolved by name is reused when looked up by its number.
+	client, _ = source.WithQuery(func(query *densify.DensifyAPIQuery) {
+		query.AccountNumber = "internal-id-42"
+	})
+	if _, account, err := cache.resolve(ctx, client); err != nil || account != "internal-id-42" {
+		t.Fatalf("resolve() = %q, %v, want %q", account, err, "internal-id-42")
+	}
+	if lookups != 5 {
+		t.Errorf("LookupAccount() calls = %d, want 5", lookups)
+	}
+
+	// Kubernetes clusters are cached like accounts.
+	for i := 0; i < 2; i++ {
+		client, _ := source.WithQuery(func(query *densify.DensifyAPIQuery) {
+			query.AnalysisTechnology = k8sPlatform
+			query.K8sCluster = "prod-cluster"
+		})
+		if _, _, err := cache.resolve(ctx, client); err != nil {
+			t.Fatalf("resolve() error = %v", err)
+		}
+	}
+	if lookups != 6 {
+		t.Errorf("LookupAccount() calls = %d, want 6", lookups)
+	}
+	client, _ = source.WithQuery(func(query *densify.DensifyAPIQuery) {
+		query.AnalysisTechnology = k8sPlatform
+	})
+	if _, _, err := cache.resolve(ctx, client); err == nil {
+		t.Error("resolve() error = nil, want a no cluster error")
+	}
+}
+
+func TestAPISourceWithResolvedAccount(t *testing.T) {
+	query := &densify.DensifyAPIQuery{AnalysisTechnology: "aws", AccountName: "prod"}
+	source := &apiSource{client: &densify.DensifyClient{BaseURL: "https://densify", Query: query}, username: "reader"}
+	sameUser := &apiSource{client: &densify.DensifyClient{BaseURL: "https://densify"}, username: "reader"}
+	otherUser := &apiSource{client: &densify.DensifyClient{BaseURL: "https://densify"}, username: "admin"}
+
+	if got := source.WithResolvedAccount(otherUser); got != source {
+		t.Errorf("WithResolvedAccount() = %v, want the source unchanged for another user", got)
+	}
+	got := source.WithResolvedAccount(sameUser)
+	if got == source || got.Name() != source.Name() || got.Query() != query {
+		t.Errorf("WithResolvedAccount() = %v, want a copy of the resolved client with the query of the source", got)
+	}
+}
@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+// densifyAutoApproveModel maps the auto_approve block of the cloud data source.
+type densifyAutoApproveModel struct {
+	MaxEffort         types.String   `tfsdk:"max_effort"`
+	MinSavings        types.Float64  `tfsdk:"min_savings"`
+	OptimizationTypes []types.String `tfsdk:"optimization_types"`
+}
+
+// effort levels reported by Densify, ordered from least to most effort.
+var effortLevels = map[string]int{
+	"none":      0,
+	"very low":  1,
+	"low":       2,
+	"medium":    3,
+	"med":       3,
+	"high":      4,
+	"very high": 5,
+}
+
+// effortRank returns the position of an effort estimate within effortLevels.
+func effortRank(effort string) (int, bool) {
+	rank, ok := effortLevels[strings.ToLower(strings.TrimSpace(effort))]
+	return rank, ok
+}
+
+// hasThresholds reports whether the auto_approve block sets at least one threshold. An empty block does not
+// auto-approve anything.
+func (policy *densifyAutoApproveModel) hasThresholds() bool {
+	return !policy.MaxEffort.IsNull() || !policy.MinSavings.IsNull() || len(policy.OptimizationTypes) > 0
+}
+
+// Validate checks that the auto_approve block sets a threshold and only references known effort levels.
+func (policy *densifyAutoApproveModel) Validate() error {
+	if !policy.hasThresholds() {
+		return fmt.Errorf("the auto_approve block must set at least one of max_effort, min_savings or optimization_types")
+	}
+	if policy.MaxEffort.IsNull() || policy.MaxEffort.IsUnknown() {
+		return nil
+	}
+	if _, ok := effortRank(policy.MaxEffort.ValueString()); !ok {
+		return fmt.Errorf("unknown max_effort value %q. Accepted values are: none, very low, low, medium, high, very high", policy.MaxEffort.ValueString())
+	}
+	return nil
+}
+
+// Evaluate decides whether the recommended type should be used as the approved type, given the type the
+// recommendation source approved, returning the decision along with a human readable reason for it.
+func (policy *densifyAutoApproveModel) Evaluate(reco *densify.DensifyRecommendation, approvedType string) (bool, string) {
+	if reco.RecommendedType == "" || reco.RecommendedType == reco.CurrentType {
+		return false, "No change recommended by Densify."
+	}
+	if approvedType == reco.RecommendedType {
+		return true, "Approved in Densify."
+	}
+	if policy == nil || !policy.hasThresholds() {
+		return false, "Awaiting approval in Densify."
+	}
+
+	reasons := []string{}
+	if !policy.MaxEffort.IsNull() {
+		maxRank, _ := effortRank(policy.MaxEffort.ValueString())
+		rank, ok := effortRank(reco.EffortEstimate)
+		if !ok || rank > maxRank {
+			return false, fmt.Sprintf("Not auto-approved: effort estimate %q exceeds max_effort %q.", reco.EffortEstimate, policy.MaxEffort.ValueString())
+		}
+		reasons = append(reasons, fmt.Sprintf("effort estimate %q is within max_effort %q", reco.EffortEstimate, policy.MaxEffort.ValueString()))
+	}
+	if !policy.MinSavings.IsNull() {
+		savings := float64(reco.SavingsEstimate)
+		if savings <= policy.MinSavings.ValueFloat64() {
+			return false, fmt.Sprintf("Not auto-approved: estimated savings of $%.2f/month does not exceed min_savings of $%.2f/month.", savings, policy.MinSavings.ValueFloat64())
+		}
+		reasons = append(reasons, fmt.Sprintf("estimated savings of $%.2f/month exceeds min_savings of $%.2f/month", savings, policy.MinSavings.ValueFloat64()))
+	}
+	if len(policy.OptimizationTypes) > 0 {
+		allowed := false
+		for _, optimizationType := range policy.OptimizationTypes {
+			if strings.EqualFold(optimizationType.ValueString(), reco.RecommendationType) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false, fmt.Sprintf("Not auto-approved: optimization type %q is not in optimization_types.", reco.RecommendationType)
+		}
+		reasons = append(reasons, fmt.Sprintf("optimization type %q is allowed", reco.RecommendationType))
+	}
+	return true, "Auto-approved: " + strings.Join(reasons, ", ") + "."
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+func TestAutoApproveEvaluate(t *testing.T) {
+	reco := &densify.DensifyRecommendation{
+		CurrentType:        "m5.xlarge",
+		RecommendedType:    "m5.large",
+		EffortEstimate:     "Low",
+		SavingsEstimate:    42,
+		RecommendationType: "Downsize",
+	}
+	tests := []struct {
+		name         string
+		policy       *densifyAutoApproveModel
+		reco         *densify.DensifyRecommendation
+		approvedType string
+		approved     bool
+		reason       string
+	}{
+		{
+			name:         "no change",
+			policy:       &densifyAutoApproveModel{MaxEffort: types.StringValue("high")},
+			reco:         &densify.DensifyRecommendation{CurrentType: "m5.large", RecommendedType: "m5.large"},
+			approvedType: "m5.large",
+			reason:       "No change recommended",
+		},
+		{
+			name:         "approved in Densify",
+			reco:         reco,
+			approvedType: "m5.large",
+			approved:     true,
+			reason:       "Approved in Densify",
+		},
+		{
+			name:         "no policy",
+			reco:         reco,
+			approvedType: "m5.xlarge",
+			reason:       "Awaiting approval",
+		},
+		{
+			name:         "empty policy",
+			policy:       &densifyAutoApproveModel{MaxEffort: types.StringNull(), MinSavings: types.Float64Null()},
+			reco:         reco,
+			approvedType: "m5.xlarge",
+			reason:       "Awaiting approval",
+		},
+		{
+			name:         "within thresholds",
+			policy:       &densifyAutoApproveModel{MaxEffort: types.StringValue("medium"), MinSavings: types.Float64Value(10), OptimizationTypes: []types.String{types.StringValue("downsize")}},
+			reco:         reco,
+			approvedType: "m5.xlarge",
+			approved:     true,
+			reason:       "Auto-approved",
+		},
+		{
+			name:         "effort too high",
+			policy:       &densifyAutoApproveModel{MaxEffort: types.StringValue("very low"), MinSavings: types.Float64Null()},
+			reco:         reco,
+			approvedType: "m5.xlarge",
+			reason:       "exceeds max_effort",
+		},
+		{
+			name:         "unknown effort",
+			policy:       &densifyAutoApproveModel{MaxEffort: types.StringValue("high"), MinSavings: types.Float64Null()},
+			reco:         &densify.DensifyRecommendation{CurrentType: "m5.xlarge", RecommendedType: "m5.large", EffortEstimate: "n/a"},
+			approvedType: "m5.xlarge",
+			reason:       "exceeds max_effort",
+		},
+		{
+			name:         "savings too low",
+			policy:       &densifyAutoApproveModel{MaxEffort: types.StringNull(), MinSavings: types.Float64Value(100)},
+			reco:         reco,
+			approvedType: "m5.xlarge",
+			reason:       "does not exceed min_savings",
+		},
+		{
+			name:         "savings equal to min_savings",
+			policy:       &densifyAutoApproveModel{MaxEffort: types.StringNull(), MinSavings: types.Float64Value(42)},
+			reco:         reco,
+			approvedType: "m5.xlarge",
+			reason:       "does not exceed min_savings",
+		},
+		{
+			name:         "optimization type not allowed",
+			policy:       &densifyAutoApproveModel{MaxEffort: types.StringNull(), MinSavings: types.Float64Null(), OptimizationTypes: []types.String{types.StringValue("Modernize")}},
+			reco:         reco,
+			approvedType: "m5.xlarge",
+			reason:       "not in optimization_types",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			approved, reason := test.policy.Evaluate(test.reco, test.approvedType)
+			if approved != test.approved {
+				t.Errorf("Evaluate() approved = %t, want %t (%s)", approved, test.approved, reason)
+			}
+			if !strings.Contains(reason, test.reason) {
+				t.Errorf("Evaluate() reason = %q, want it to contain %q", reason, test.reason)
+			}
+		})
+	}
+}
+
+func TestAutoApproveValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  densifyAutoApproveModel
+		wantErr bool
+	}{
+		{"empty", densifyAutoApproveModel{MaxEffort: types.StringNull(), MinSavings: types.Float64Null()}, true},
+		{"unknown effort", densifyAutoApproveModel{MaxEffort: types.StringValue("tiny"), MinSavings: types.Float64Null()}, true},
+		{"max effort", densifyAutoApproveModel{MaxEffort: types.StringValue("Very Low"), MinSavings: types.Float64Null()}, false},
+		{"min savings", densifyAutoApproveModel{MaxEffort: types.StringNull(), MinSavings: types.Float64Value(0)}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.policy.Validate(); (err != nil) != test.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %t", err, test.wantErr)
+			}
+		})
+	}
+}
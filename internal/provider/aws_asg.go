@@ -0,0 +1,398 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &densifyDataSourceAWSASG{}
+	_ datasource.DataSourceWithConfigure = &densifyDataSourceAWSASG{}
+)
+
+// NewDensifyDataSourceAWSASG is a helper function to simplify the provider implementation.
+func NewDensifyDataSourceAWSASG() datasource.DataSource {
+	return &densifyDataSourceAWSASG{}
+}
+
+// densifyDataSourceAWSASG is the data source implementation.
+type densifyDataSourceAWSASG struct {
+	provider *densifyProviderData
+}
+
+// densifyDataSourceAWSASGModel maps AWS Auto Scaling Group Recommendation schema data.
+type densifyDataSourceAWSASGModel struct {
+	// lookup arguments
+	AccountNumber types.String `tfsdk:"account_number"`
+	AccountName   types.String `tfsdk:"account_name"`
+	Name          types.String `tfsdk:"name"`
+
+	EntityId         types.String  `tfsdk:"entity_id"`
+	Arn              types.String  `tfsdk:"arn"`
+	OptimizationType types.String  `tfsdk:"optimization_type"`
+	ApprovalType     types.String  `tfsdk:"approval_type"`
+	SavingsEstimate  types.Float64 `tfsdk:"savings_estimate"`
+	EffortEstimate   types.String  `tfsdk:"effort_estimate"`
+
+	CurrentInstance     types.String `tfsdk:"current_type"`
+	RecommendedInstance types.String `tfsdk:"recommended_type"`
+	ApprovedInstance    types.String `tfsdk:"approved_type"`
+
+	CurrentMinSize             types.Int64 `tfsdk:"current_min_size"`
+	RecommendedMinSize         types.Int64 `tfsdk:"recommended_min_size"`
+	CurrentMaxSize             types.Int64 `tfsdk:"current_max_size"`
+	RecommendedMaxSize         types.Int64 `tfsdk:"recommended_max_size"`
+	CurrentDesiredCapacity     types.Int64 `tfsdk:"current_desired_capacity"`
+	RecommendedDesiredCapacity types.Int64 `tfsdk:"recommended_desired_capacity"`
+
+	RecommendedWeightedMinSize         types.Int64 `tfsdk:"recommended_weighted_min_size"`
+	RecommendedWeightedMaxSize         types.Int64 `tfsdk:"recommended_weighted_max_size"`
+	RecommendedWeightedDesiredCapacity types.Int64 `tfsdk:"recommended_weighted_desired_capacity"`
+
+	Overrides []densifyDataSourceASGOverrideModel `tfsdk:"mixed_instances_overrides"`
+}
+
+// densifyDataSourceASGOverrideModel matches the override block of an aws_autoscaling_group mixed_instances_policy.
+type densifyDataSourceASGOverrideModel struct {
+	InstanceType     types.String `tfsdk:"instance_type"`
+	WeightedCapacity types.String `tfsdk:"weighted_capacity"`
+}
+
+// Metadata returns the data source type name.
+func (d *densifyDataSourceAWSASG) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_aws_asg"
+}
+
+// Schema defines the schema for the data source.
+func (d *densifyDataSourceAWSASG) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches a Recommendation for an AWS Auto Scaling Group from the Densify API, in a shape that can be used by aws_autoscaling_group and aws_launch_template.",
+		Attributes: map[string]schema.Attribute{
+			"account_number": accountNumberSchemaAttribute(),
+			"account_name":   accountNameSchemaAttribute(),
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Auto Scaling Group name.",
+			},
+
+			"entity_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for cloud resource.",
+			},
+			"arn": schema.StringAttribute{
+				Computed:    true,
+				Description: "Auto Scaling Group ARN.",
+			},
+			"optimization_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Type of optimization. Ex. Downsize, Upsize, Terminate, etc.",
+			},
+			"approval_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Approval type. If ITSM integration has been enabled, this field will identify whether the recommendation has been reviewed & approved.",
+			},
+			"savings_estimate": schema.Float64Attribute{
+				Computed:    true,
+				Description: "Estimated monthly savings by applying the optimization recommendation.",
+			},
+			"effort_estimate": schema.StringAttribute{
+				Computed:    true,
+				Description: "Estimated effort required by applying optimization recommendation. Ex. none, low, med, high.",
+			},
+			"current_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Current instance type of the group.",
+			},
+			"recommended_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Recommended instance type generated by Densify. Use it for the aws_launch_template instance_type.",
+			},
+			"approved_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "The approved instance type. This starts with the fallback instance or the current instance type, and may only be replaced by the recommended instance if 'Approval_Type' is set.",
+			},
+			"current_min_size": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Current minimum size of the group.",
+			},
+			"recommended_min_size": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Recommended minimum size of the group, in instances of the recommended type (aws_autoscaling_group min_size without weighted overrides). The current size when Densify has no group recommendation.",
+			},
+			"current_max_size": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Current maximum size of the group.",
+			},
+			"recommended_max_size": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Recommended maximum size of the group, in instances of the recommended type (aws_autoscaling_group max_size without weighted overrides). The current size when Densify has no group recommendation.",
+			},
+			"current_desired_capacity": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Current average number of instances running in the group.",
+			},
+			"recommended_desired_capacity": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Recommended desired capacity of the group, in instances of the recommended type (aws_autoscaling_group desired_capacity without weighted overrides), within the recommended minimum and maximum sizes. Based on the current average number of instances when Densify has no group recommendation.",
+			},
+			"recommended_weighted_min_size": schema.Int64Attribute{
+				Computed:    true,
+				Description: "recommended_min_size in capacity units: multiplied by the weighted_capacity of the recommended type, for aws_autoscaling_group min_size with the weighted mixed_instances_overrides. Null when the weight is null.",
+			},
+			"recommended_weighted_max_size": schema.Int64Attribute{
+				Computed:    true,
+				Description: "recommended_max_size in capacity units: multiplied by the weighted_capacity of the recommended type, for aws_autoscaling_group max_size with the weighted mixed_instances_overrides. Null when the weight is null.",
+			},
+			"recommended_weighted_desired_capacity": schema.Int64Attribute{
+				Computed:    true,
+				Description: "recommended_desired_capacity in capacity units: multiplied by the weighted_capacity of the recommended type, for aws_autoscaling_group desired_capacity with the weighted mixed_instances_overrides. Null when the weight is null.",
+			},
+			"mixed_instances_overrides": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Instance type overrides, in priority order, for the launch_template override blocks of an aws_autoscaling_group mixed_instances_policy.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"instance_type": schema.StringAttribute{
+							Computed:    true,
+							Description: "Override instance type.",
+						},
+						"weighted_capacity": schema.StringAttribute{
+							Computed:    true,
+							Description: "Number of capacity units provided by the instance type: its vCPUs relative to the smaller instance type of the list. Null when the vCPU counts are unknown or not multiples of each other. When the weights are used, the group sizes are in capacity units: use the recommended_weighted_* sizes.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *densifyDataSourceAWSASG) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	tflog.Trace(ctx, "Configuring Densify API client")
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*densifyProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *densifyProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = providerData
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *densifyDataSourceAWSASG) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Trace(ctx, "Reading Densify API client")
+	ctx, span := startSpan(ctx, "densify_aws_asg.Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+	var state densifyDataSourceAWSASGModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	source, ok := d.provider.readSource(ctx, req, resp, cloudAttributes)
+	if !ok {
+		return
+	}
+
+	client, err := source.WithQuery(func(query *densify.DensifyAPIQuery) {
+		setAccount(query, state.AccountNumber, state.AccountName)
+		query.AnalysisTechnology = "aws"
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create Densify query",
+			"Densify Client Query Error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Densify API client: calling GetAccountOrCluster")
+	client, _, err = d.provider.accounts.resolve(ctx, client)
+	if err != nil {
+		if client.Query().SkipErrors {
+			// skip the error message
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Account Number/Name",
+			err.Error()+d.provider.settings.describeAccount(state.AccountNumber, state.AccountName),
+		)
+		return
+	}
+	tflog.Trace(ctx, "Densify API client: GetAccountOrCluster: success")
+	tflog.Debug(ctx, "Densify API client: calling GetDensifyRecommendations")
+	recos, err := client.Recommendations()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Recommendation",
+			err.Error(),
+		)
+		return
+	}
+	tflog.Trace(ctx, "Densify API client: GetDensifyRecommendations: success")
+
+	name := state.Name.ValueString()
+	matches := filterRecommendations(recos, func(reco *densify.DensifyRecommendation) bool {
+		return isAutoScalingGroup(reco) && reco.Name == name
+	})
+	tflog.Debug(ctx, fmt.Sprintf(`Num of matching Auto Scaling Group recommendations: %d`, len(matches)))
+	if len(matches) > 1 {
+		resp.Diagnostics.AddError(
+			"Ambiguous Densify Recommendation",
+			fmt.Sprintf("Found %d Auto Scaling Group recommendations named %q. Set account_number to select a single account.", len(matches), name),
+		)
+		return
+	}
+	if len(matches) == 0 {
+		if client.Query().SkipErrors {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Recommendation",
+			fmt.Sprintf("No Auto Scaling Group recommendation matches name %q.", name),
+		)
+		return
+	}
+	reco := matches[0]
+
+	// Map response body to model
+	state.EntityId = types.StringValue(reco.EntityId)
+	state.Arn = types.StringValue(reco.ResourceId)
+	state.OptimizationType = types.StringValue(reco.RecommendationType)
+	state.ApprovalType = types.StringValue(reco.ApprovalType)
+	state.SavingsEstimate = types.Float64Value(float64(reco.SavingsEstimate))
+	state.EffortEstimate = types.StringValue(reco.EffortEstimate)
+	state.CurrentInstance = types.StringValue(reco.CurrentType)
+	state.RecommendedInstance = types.StringValue(reco.RecommendedType)
+	state.ApprovedInstance = types.StringValue(client.ApprovedType(&reco))
+
+	minSize, maxSize, desired := recommendedGroupSizes(&reco)
+	state.CurrentMinSize = types.Int64Value(int64(reco.MinGroupCurrent))
+	state.RecommendedMinSize = types.Int64Value(minSize)
+	state.CurrentMaxSize = types.Int64Value(int64(reco.MaxGroupCurrent))
+	state.RecommendedMaxSize = types.Int64Value(maxSize)
+	state.CurrentDesiredCapacity = types.Int64Value(int64(math.Ceil(float64(reco.AvgInstanceCountCurrent))))
+	state.RecommendedDesiredCapacity = types.Int64Value(desired)
+	state.Overrides = mixedInstancesOverrides(&reco)
+	weight := recommendedWeight(state.Overrides)
+	state.RecommendedWeightedMinSize = weightedSize(minSize, weight)
+	state.RecommendedWeightedMaxSize = weightedSize(maxSize, weight)
+	state.RecommendedWeightedDesiredCapacity = weightedSize(desired, weight)
+
+	recordResult(ctx, sourceResult(client))
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// isAutoScalingGroup reports whether a recommendation is for an Auto Scaling Group rather than a single instance.
+func isAutoScalingGroup(reco *densify.DensifyRecommendation) bool {
+	serviceType := strings.ToLower(reco.ServiceType)
+	return serviceType == "asg" || strings.Contains(serviceType, "auto scaling") || strings.Contains(reco.ResourceId, ":autoScalingGroup:")
+}
+
+// recommendedGroupSizes returns the recommended minimum, maximum and desired sizes of a group. Densify leaves the
+// recommended sizes at 0 when it has no group recommendation, so the current sizes (and average instance count) are
+// kept in that case instead of scaling the group to zero.
+func recommendedGroupSizes(reco *densify.DensifyRecommendation) (int64, int64, int64) {
+	minSize, maxSize := reco.MinGroupRecommended, reco.MaxGroupRecommended
+	if minSize <= 0 && maxSize <= 0 {
+		minSize, maxSize = reco.MinGroupCurrent, reco.MaxGroupCurrent
+	}
+	avgInstances := reco.AvgInstanceCountRecommended
+	if avgInstances <= 0 {
+		avgInstances = reco.AvgInstanceCountCurrent
+	}
+	return int64(minSize), int64(maxSize), desiredCapacity(avgInstances, minSize, maxSize)
+}
+
+// desiredCapacity rounds the average instance count up, keeping it within the minimum and maximum group sizes.
+func desiredCapacity(avgInstances float32, minSize int, maxSize int) int64 {
+	desired := int64(math.Ceil(float64(avgInstances)))
+	if desired < int64(minSize) {
+		desired = int64(minSize)
+	}
+	if maxSize > 0 && desired > int64(maxSize) {
+		desired = int64(maxSize)
+	}
+	return desired
+}
+
+// mixedInstancesOverrides lists the recommended instance type first, followed by the current instance type so
+// capacity can still be met while the group is replaced. Both types are weighted by their vCPUs relative to the
+// smaller one, and the weights are left null when the vCPU counts are unknown or not multiples of each other.
+func mixedInstancesOverrides(reco *densify.DensifyRecommendation) []densifyDataSourceASGOverrideModel {
+	overrides := []densifyDataSourceASGOverrideModel{}
+	if reco.RecommendedType == "" {
+		return overrides
+	}
+	if reco.CurrentType == "" || reco.CurrentType == reco.RecommendedType {
+		return append(overrides, densifyDataSourceASGOverrideModel{
+			InstanceType:     types.StringValue(reco.RecommendedType),
+			WeightedCapacity: types.StringValue("1"),
+		})
+	}
+
+	recommendedWeight := types.StringNull()
+	currentWeight := types.StringNull()
+	if smaller := min(reco.RecommendedCpu, reco.CurrentCpu); smaller > 0 && reco.RecommendedCpu%smaller == 0 && reco.CurrentCpu%smaller == 0 {
+		recommendedWeight = types.StringValue(strconv.Itoa(reco.RecommendedCpu / smaller))
+		currentWeight = types.StringValue(strconv.Itoa(reco.CurrentCpu / smaller))
+	}
+	return append(overrides,
+		densifyDataSourceASGOverrideModel{
+			InstanceType:     types.StringValue(reco.RecommendedType),
+			WeightedCapacity: recommendedWeight,
+		},
+		densifyDataSourceASGOverrideModel{
+			InstanceType:     types.StringValue(reco.CurrentType),
+			WeightedCapacity: currentWeight,
+		},
+	)
+}
+
+// recommendedWeight returns the weighted capacity of the recommended instance type, the first override: 1 when
+// there are no overrides, or 0 when its weight is null.
+func recommendedWeight(overrides []densifyDataSourceASGOverrideModel) int64 {
+	if len(overrides) == 0 {
+		return 1
+	}
+	weight, err := strconv.ParseInt(overrides[0].WeightedCapacity.ValueString(), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return weight
+}
+
+// weightedSize converts a group size in instances to capacity units, or returns null when the weight is unknown.
+func weightedSize(size int64, weight int64) types.Int64 {
+	if weight <= 0 {
+		return types.Int64Null()
+	}
+	return types.Int64Value(size * weight)
+}
@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+func TestMixedInstancesOverrides(t *testing.T) {
+	tests := []struct {
+		name string
+		reco densify.DensifyRecommendation
+		want []densifyDataSourceASGOverrideModel
+	}{
+		{
+			name: "no recommendation",
+			reco: densify.DensifyRecommendation{CurrentType: "m5.large"},
+			want: []densifyDataSourceASGOverrideModel{},
+		},
+		{
+			name: "no change",
+			reco: densify.DensifyRecommendation{CurrentType: "m5.large", RecommendedType: "m5.large", CurrentCpu: 2, RecommendedCpu: 2},
+			want: []densifyDataSourceASGOverrideModel{
+				{InstanceType: types.StringValue("m5.large"), WeightedCapacity: types.StringValue("1")},
+			},
+		},
+		{
+			name: "downsize",
+			reco: densify.DensifyRecommendation{CurrentType: "m5.2xlarge", RecommendedType: "m5.large", CurrentCpu: 8, RecommendedCpu: 2},
+			want: []densifyDataSourceASGOverrideModel{
+				{InstanceType: types.StringValue("m5.large"), WeightedCapacity: types.StringValue("1")},
+				{InstanceType: types.StringValue("m5.2xlarge"), WeightedCapacity: types.StringValue("4")},
+			},
+		},
+		{
+			name: "upsize",
+			reco: densify.DensifyRecommendation{CurrentType: "m5.large", RecommendedType: "m5.xlarge", CurrentCpu: 2, RecommendedCpu: 4},
+			want: []densifyDataSourceASGOverrideModel{
+				{InstanceType: types.StringValue("m5.xlarge"), WeightedCapacity: types.StringValue("2")},
+				{InstanceType: types.StringValue("m5.large"), WeightedCapacity: types.StringValue("1")},
+			},
+		},
+		{
+			name: "same vCPUs",
+			reco: densify.DensifyRecommendation{CurrentType: "m4.large", RecommendedType: "m6i.large", CurrentCpu: 2, RecommendedCpu: 2},
+			want: []densifyDataSourceASGOverrideModel{
+				{InstanceType: types.StringValue("m6i.large"), WeightedCapacity: types.StringValue("1")},
+				{InstanceType: types.StringValue("m4.large"), WeightedCapacity: types.StringValue("1")},
+			},
+		},
+		{
+			name: "large upsize",
+			reco: densify.DensifyRecommendation{CurrentType: "c5.xlarge", RecommendedType: "c5.9xlarge", CurrentCpu: 4, RecommendedCpu: 36},
+			want: []densifyDataSourceASGOverrideModel{
+				{InstanceType: types.StringValue("c5.9xlarge"), WeightedCapacity: types.StringValue("9")},
+				{InstanceType: types.StringValue("c5.xlarge"), WeightedCapacity: types.StringValue("1")},
+			},
+		},
+		{
+			name: "not multiples",
+			reco: densify.DensifyRecommendation{CurrentType: "m5.xlarge", RecommendedType: "m5a.large", CurrentCpu: 6, RecommendedCpu: 4},
+			want: []densifyDataSourceASGOverrideModel{
+				{InstanceType: types.StringValue("m5a.large"), WeightedCapacity: types.StringNull()},
+				{InstanceType: types.StringValue("m5.xlarge"), WeightedCapacity: types.StringNull()},
+			},
+		},
+		{
+			name: "unknown vCPUs",
+			reco: densify.DensifyRecommendation{CurrentType: "m5.xlarge", RecommendedType: "m5.large"},
+			want: []densifyDataSourceASGOverrideModel{
+				{InstanceType: types.StringValue("m5.large"), WeightedCapacity: types.StringNull()},
+				{InstanceType: types.StringValue("m5.xlarge"), WeightedCapacity: types.StringNull()},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := mixedInstancesOverrides(&test.reco)
+			if len(got) != len(test.want) {
+				t.Fatalf("mixedInstancesOverrides() = %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if !got[i].InstanceType.Equal(test.want[i].InstanceType) || !got[i].WeightedCapacity.Equal(test.want[i].WeightedCapacity) {
+					t.Errorf("mixedInstancesOverrides()[%d] = %v, want %v", i, got[i], test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDesiredCapacity(t *testing.T) {
+	tests := []struct {
+		name         string
+		avgInstances float32
+		minSize      int
+		maxSize      int
+		want         int64
+	}{
+		{"rounded up", 2.2, 1, 10, 3},
+		{"whole", 4, 1, 10, 4},
+		{"below minimum", 0.5, 2, 10, 2},
+		{"above maximum", 12.1, 1, 10, 10},
+		{"no maximum", 12.1, 1, 0, 13},
+		{"empty group", 0, 0, 0, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := desiredCapacity(test.avgInstances, test.minSize, test.maxSize); got != test.want {
+				t.Errorf("desiredCapacity(%g, %d, %d) = %d, want %d", test.avgInstances, test.minSize, test.maxSize, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRecommendedGroupSizes(t *testing.T) {
+	tests := []struct {
+		name        string
+		reco        densify.DensifyRecommendation
+		wantMin     int64
+		wantMax     int64
+		wantDesired int64
+	}{
+		{
+			name:        "group recommendation",
+			reco:        densify.DensifyRecommendation{MinGroupCurrent: 2, MaxGroupCurrent: 10, AvgInstanceCountCurrent: 6, MinGroupRecommended: 1, MaxGroupRecommended: 4, AvgInstanceCountRecommended: 2.5},
+			wantMin:     1,
+			wantMax:     4,
+			wantDesired: 3,
+		},
+		{
+			name:        "scale to zero",
+			reco:        densify.DensifyRecommendation{MinGroupCurrent: 2, MaxGroupCurrent: 10, AvgInstanceCountCurrent: 6, MaxGroupRecommended: 4, AvgInstanceCountRecommended: 0.4},
+			wantMin:     0,
+			wantMax:     4,
+			wantDesired: 1,
+		},
+		{
+			name:        "no group recommendation",
+			reco:        densify.DensifyRecommendation{MinGroupCurrent: 2, MaxGroupCurrent: 10, AvgInstanceCountCurrent: 5.5},
+			wantMin:     2,
+			wantMax:     10,
+			wantDesired: 6,
+		},
+		{
+			name:        "no average instance count",
+			reco:        densify.DensifyRecommendation{MinGroupCurrent: 2, MaxGroupCurrent: 10, MinGroupRecommended: 3, MaxGroupRecommended: 8},
+			wantMin:     3,
+			wantMax:     8,
+			wantDesired: 3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			minSize, maxSize, desired := recommendedGroupSizes(&test.reco)
+			if minSize != test.wantMin || maxSize != test.wantMax || desired != test.wantDesired {
+				t.Errorf("recommendedGroupSizes() = %d, %d, %d, want %d, %d, %d", minSize, maxSize, desired, test.wantMin, test.wantMax, test.wantDesired)
+			}
+		})
+	}
+}
+
+func TestWeightedGroupSizes(t *testing.T) {
+	tests := []struct {
+		name string
+		reco densify.DensifyRecommendation
+		want types.Int64
+	}{
+		{"recommended type twice as large", densify.DensifyRecommendation{CurrentType: "m5.large", RecommendedType: "m5.xlarge", CurrentCpu: 2, RecommendedCpu: 4}, types.Int64Value(6)},
+		{"recommended type half as large", densify.DensifyRecommendation{CurrentType: "m5.xlarge", RecommendedType: "m5.large", CurrentCpu: 4, RecommendedCpu: 2}, types.Int64Value(3)},
+		{"unknown vCPUs", densify.DensifyRecommendation{CurrentType: "m5.xlarge", RecommendedType: "m5.large"}, types.Int64Null()},
+		{"same type", densify.DensifyRecommendation{CurrentType: "m5.large", RecommendedType: "m5.large"}, types.Int64Value(3)},
+		{"no recommended type", densify.DensifyRecommendation{CurrentType: "m5.large"}, types.Int64Value(3)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := weightedSize(3, recommendedWeight(mixedInstancesOverrides(&test.reco))); !got.Equal(test.want) {
+				t.Errorf("weightedSize(3) = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,413 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &densifyDataSourceAzureVM{}
+	_ datasource.DataSourceWithConfigure = &densifyDataSourceAzureVM{}
+)
+
+// NewDensifyDataSourceAzureVM is a helper function to simplify the provider implementation.
+func NewDensifyDataSourceAzureVM() datasource.DataSource {
+	return &densifyDataSourceAzureVM{}
+}
+
+// densifyDataSourceAzureVM is the data source implementation.
+type densifyDataSourceAzureVM struct {
+	provider *densifyProviderData
+}
+
+// densifyDataSourceAzureVMModel maps Azure VM / VMSS Recommendation schema data.
+type densifyDataSourceAzureVMModel struct {
+	// lookup arguments
+	AccountNumber types.String `tfsdk:"account_number"`
+	AccountName   types.String `tfsdk:"account_name"`
+	ResourceId    types.String `tfsdk:"resource_id"`
+	ResourceGroup types.String `tfsdk:"resource_group"`
+	VMName        types.String `tfsdk:"vm_name"`
+	VMSSName      types.String `tfsdk:"vmss_name"`
+
+	EntityId             types.String                      `tfsdk:"entity_id"`
+	Name                 types.String                      `tfsdk:"name"`
+	CurrentSize          types.String                      `tfsdk:"current_size"`
+	RecommendedSize      types.String                      `tfsdk:"recommended_size"`
+	ApprovedSize         types.String                      `tfsdk:"approved_size"`
+	CurrentSkuFamily     types.String                      `tfsdk:"current_sku_family"`
+	RecommendedSkuFamily types.String                      `tfsdk:"recommended_sku_family"`
+	OptimizationType     types.String                      `tfsdk:"optimization_type"`
+	ApprovalType         types.String                      `tfsdk:"approval_type"`
+	SavingsEstimate      types.Float64                     `tfsdk:"savings_estimate"`
+	EffortEstimate       types.String                      `tfsdk:"effort_estimate"`
+	SubscriptionId       types.String                      `tfsdk:"subscription_id"`
+	Disks                []densifyDataSourceAzureDiskModel `tfsdk:"disks"`
+}
+
+type densifyDataSourceAzureDiskModel struct {
+	Name              types.String `tfsdk:"name"`
+	CurrentSku        types.String `tfsdk:"current_sku"`
+	RecommendedSku    types.String `tfsdk:"recommended_sku"`
+	CurrentSizeGB     types.Int64  `tfsdk:"current_size_gb"`
+	RecommendedSizeGB types.Int64  `tfsdk:"recommended_size_gb"`
+}
+
+// Metadata returns the data source type name.
+func (d *densifyDataSourceAzureVM) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_azure_vm"
+}
+
+// Schema defines the schema for the data source.
+func (d *densifyDataSourceAzureVM) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches a Recommendation for an Azure Virtual Machine or Virtual Machine Scale Set from the Densify API. Look up by resource_id, by resource_group and vm_name, or by vmss_name.",
+		Attributes: map[string]schema.Attribute{
+			"account_number": accountNumberSchemaAttribute(),
+			"account_name":   accountNameSchemaAttribute(),
+			"resource_id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Azure resource ID of the virtual machine or scale set. Ex. /subscriptions/{id}/resourceGroups/{rg}/providers/Microsoft.Compute/virtualMachines/{name}.",
+			},
+			"resource_group": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Azure resource group of the virtual machine or scale set. Required with vm_name, optional with vmss_name.",
+			},
+			"vm_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Azure virtual machine name.",
+			},
+			"vmss_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Azure virtual machine scale set name.",
+			},
+
+			"entity_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for cloud resource.",
+			},
+			"name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the virtual machine or scale set.",
+			},
+			"subscription_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Azure subscription ID of the virtual machine or scale set.",
+			},
+			"current_size": schema.StringAttribute{
+				Computed:    true,
+				Description: "Current VM size. Ex. Standard_D4s_v3.",
+			},
+			"recommended_size": schema.StringAttribute{
+				Computed:    true,
+				Description: "Recommended VM size generated by Densify.",
+			},
+			"approved_size": schema.StringAttribute{
+				Computed:    true,
+				Description: "The approved VM size. This starts with the fallback_instance_type or the current VM size, and may only be replaced by the recommended VM size if 'Approval_Type' is set.",
+			},
+			"current_sku_family": schema.StringAttribute{
+				Computed:    true,
+				Description: "Azure SKU (quota) family of the current VM size. Ex. standardDSv3Family.",
+			},
+			"recommended_sku_family": schema.StringAttribute{
+				Computed:    true,
+				Description: "Azure SKU (quota) family of the recommended VM size. Ex. standardDSv3Family.",
+			},
+			"optimization_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Type of optimization. Ex. Downsize, Upsize, Terminate, etc.",
+			},
+			"approval_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Approval type. If ITSM integration has been enabled, this field will identify whether the recommendation has been reviewed & approved.",
+			},
+			"savings_estimate": schema.Float64Attribute{
+				Computed:    true,
+				Description: "Estimated monthly savings by applying the optimization recommendation.",
+			},
+			"effort_estimate": schema.StringAttribute{
+				Computed:    true,
+				Description: "Estimated effort required by applying optimization recommendation. Ex. none, low, med, high.",
+			},
+			"disks": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Managed disk recommendations for the virtual machine.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Managed disk name.",
+						},
+						"current_sku": schema.StringAttribute{
+							Computed:    true,
+							Description: "Current disk SKU. Ex. Premium_LRS.",
+						},
+						"recommended_sku": schema.StringAttribute{
+							Computed:    true,
+							Description: "Recommended disk SKU generated by Densify.",
+						},
+						"current_size_gb": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Current disk size (in gigabytes or GB).",
+						},
+						"recommended_size_gb": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Recommended disk size (in gigabytes or GB).",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *densifyDataSourceAzureVM) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	tflog.Trace(ctx, "Configuring Densify API client")
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*densifyProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *densifyProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = providerData
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *densifyDataSourceAzureVM) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Trace(ctx, "Reading Densify API client")
+	ctx, span := startSpan(ctx, "densify_azure_vm.Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+	var state densifyDataSourceAzureVMModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	source, ok := d.provider.readSource(ctx, req, resp, cloudAttributes)
+	if !ok {
+		return
+	}
+
+	match, err := state.matcher()
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("resource_id"),
+			"Invalid Azure VM Lookup",
+			err.Error(),
+		)
+		return
+	}
+
+	client, err := source.WithQuery(func(query *densify.DensifyAPIQuery) {
+		setAccount(query, state.AccountNumber, state.AccountName)
+		query.AnalysisTechnology = "azure"
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create Densify query",
+			"Densify Client Query Error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Densify API client: calling GetAccountOrCluster")
+	client, _, err = d.provider.accounts.resolve(ctx, client)
+	if err != nil {
+		if client.Query().SkipErrors {
+			// skip the error message
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Account Number/Name",
+			err.Error()+d.provider.settings.describeAccount(state.AccountNumber, state.AccountName),
+		)
+		return
+	}
+	tflog.Trace(ctx, "Densify API client: GetAccountOrCluster: success")
+	tflog.Debug(ctx, "Densify API client: calling GetDensifyRecommendations")
+	recos, err := client.Recommendations()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Recommendation",
+			err.Error(),
+		)
+		return
+	}
+	tflog.Trace(ctx, "Densify API client: GetDensifyRecommendations: success")
+
+	matches := filterRecommendations(recos, match)
+	tflog.Debug(ctx, fmt.Sprintf(`Num of matching Azure recommendations: %d`, len(matches)))
+	if len(matches) > 1 {
+		resp.Diagnostics.AddError(
+			"Ambiguous Densify Recommendation",
+			fmt.Sprintf("Found %d Azure recommendations matching the lookup: %s. Set resource_id, set resource_group with vmss_name, or set account_number to select a single virtual machine or scale set.", len(matches), ambiguousMatches(matches)),
+		)
+		return
+	}
+	if len(matches) == 0 {
+		if client.Query().SkipErrors {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Recommendation",
+			"No Azure virtual machine or scale set recommendation matches the lookup.",
+		)
+		return
+	}
+	reco := matches[0]
+
+	// Map response body to model
+	resourceID := parseResourcePath(reco.ResourceId)
+	state.ResourceId = types.StringValue(reco.ResourceId)
+	state.ResourceGroup = types.StringValue(resourceID.get("resourceGroups"))
+	state.SubscriptionId = types.StringValue(resourceID.get("subscriptions"))
+	state.EntityId = types.StringValue(reco.EntityId)
+	state.Name = types.StringValue(reco.Name)
+	state.CurrentSize = types.StringValue(reco.CurrentType)
+	state.RecommendedSize = types.StringValue(reco.RecommendedType)
+	state.ApprovedSize = types.StringValue(client.ApprovedType(&reco))
+	state.CurrentSkuFamily = types.StringValue(azureSkuFamily(reco.CurrentType))
+	state.RecommendedSkuFamily = types.StringValue(azureSkuFamily(reco.RecommendedType))
+	state.OptimizationType = types.StringValue(reco.RecommendationType)
+	state.ApprovalType = types.StringValue(reco.ApprovalType)
+	state.SavingsEstimate = types.Float64Value(float64(reco.SavingsEstimate))
+	state.EffortEstimate = types.StringValue(reco.EffortEstimate)
+
+	state.Disks = []densifyDataSourceAzureDiskModel{}
+	for _, disk := range reco.Disks {
+		state.Disks = append(state.Disks, densifyDataSourceAzureDiskModel{
+			Name:              types.StringValue(disk.Name),
+			CurrentSku:        types.StringValue(disk.CurrentType),
+			RecommendedSku:    types.StringValue(disk.RecommendedType),
+			CurrentSizeGB:     types.Int64Value(int64(disk.CurrentSize)),
+			RecommendedSizeGB: types.Int64Value(int64(disk.RecommendedSize)),
+		})
+	}
+
+	recordResult(ctx, sourceResult(client))
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// matcher returns a function that matches recommendations against the configured lookup arguments.
+func (config *densifyDataSourceAzureVMModel) matcher() (func(reco *densify.DensifyRecommendation) bool, error) {
+	switch {
+	case !config.ResourceId.IsNull():
+		resourceID := config.ResourceId.ValueString()
+		return func(reco *densify.DensifyRecommendation) bool {
+			return matchesResourceID(reco, resourceID)
+		}, nil
+
+	case !config.VMName.IsNull():
+		if config.ResourceGroup.IsNull() {
+			return nil, fmt.Errorf("resource_group is required when looking up a virtual machine by vm_name")
+		}
+		return azureNameMatcher("virtualMachines", config.VMName.ValueString(), config.ResourceGroup.ValueString()), nil
+
+	case !config.VMSSName.IsNull():
+		return azureNameMatcher("virtualMachineScaleSets", config.VMSSName.ValueString(), config.ResourceGroup.ValueString()), nil
+	}
+	return nil, fmt.Errorf("one of resource_id, vm_name (with resource_group) or vmss_name must be set")
+}
+
+// azureNameMatcher matches recommendations whose resource ID has the given resource type and name,
+// within resourceGroup when it is not empty.
+func azureNameMatcher(resourceType string, name string, resourceGroup string) func(reco *densify.DensifyRecommendation) bool {
+	return func(reco *densify.DensifyRecommendation) bool {
+		resourceID := parseResourcePath(reco.ResourceId)
+		if !strings.EqualFold(resourceID.get(resourceType), name) {
+			return false
+		}
+		// scale set instances are reported under the scale set, so skip them.
+		if resourceType == "virtualMachineScaleSets" && resourceID.get("virtualMachines") != "" {
+			return false
+		}
+		return resourceGroup == "" || strings.EqualFold(resourceID.get("resourceGroups"), resourceGroup)
+	}
+}
+
+// azureSkuFamilies maps the SKU families that do not follow the naming rules of azureSkuFamily, keyed by the
+// lower case name derived from the VM size.
+var azureSkuFamilies = map[string]string{
+	"standardafamily":           "standardA0_A7Family",
+	"standardhbrsfamily":        "standardHBSFamily",
+	"standardhbrsv2family":      "standardHBrsv2Family",
+	"standardhbrsv3family":      "standardHBrsv3Family",
+	"standardhcrsfamily":        "standardHCSFamily",
+	"standardncadsa100v4family": "StandardNCADSA100v4Family",
+}
+
+// azureSkuFamily derives the Azure SKU (quota) family from a VM size, ex. Standard_D4s_v3 -> standardDSv3Family.
+// The family is made of the tier, the series letters, the additive features (ex. s, d, a, p) without the memory
+// intensive m, the accelerator and the version. Families named otherwise are listed in azureSkuFamilies.
+func azureSkuFamily(size string) string {
+	if size == "" {
+		return ""
+	}
+	parts := strings.Split(size, "_")
+	tier := "standard"
+	if len(parts) > 1 && (strings.EqualFold(parts[0], "standard") || strings.EqualFold(parts[0], "basic")) {
+		tier = strings.ToLower(parts[0])
+		parts = parts[1:]
+	}
+
+	// the series letters come first, then the vCPU count (ex. 4 or 4-2 for constrained vCPUs), then the features.
+	family := strings.Builder{}
+	features := strings.Builder{}
+	seenDigit := false
+	for _, r := range parts[0] {
+		switch {
+		case unicode.IsDigit(r) || r == '-':
+			seenDigit = true
+		case !seenDigit:
+			family.WriteRune(unicode.ToUpper(r))
+		case unicode.ToLower(r) != 'm': // memory intensive sizes share the family of their base size.
+			features.WriteRune(unicode.ToUpper(r))
+		}
+	}
+
+	accelerator := ""
+	version := ""
+	for _, part := range parts[1:] {
+		if len(part) > 1 && (part[0] == 'v' || part[0] == 'V') && unicode.IsDigit(rune(part[1])) {
+			version = strings.ToLower(part)
+		} else {
+			accelerator += strings.ToUpper(part)
+		}
+	}
+
+	name := tier + family.String() + features.String() + accelerator + version + "Family"
+	if known, ok := azureSkuFamilies[strings.ToLower(name)]; ok {
+		return known
+	}
+	return name
+}
@@ -0,0 +1,43 @@
+package provider
+
+import "testing"
+
+func TestAzureSkuFamily(t *testing.T) {
+	tests := []struct {
+		size string
+		want string
+	}{
+		{"", ""},
+		{"Standard_D2_v2", "standardDv2Family"},
+		{"Standard_DS2_v2", "standardDSv2Family"},
+		{"Standard_D4s_v3", "standardDSv3Family"},
+		{"Standard_D4ds_v4", "standardDDSv4Family"},
+		{"Standard_D4as_v4", "standardDASv4Family"},
+		{"Standard_D2ps_v5", "standardDPSv5Family"},
+		{"Standard_E8ds_v5", "standardEDSv5Family"},
+		{"Standard_E4-2s_v3", "standardESv3Family"},
+		{"Standard_F4", "standardFFamily"},
+		{"Standard_F4s_v2", "standardFSv2Family"},
+		{"Standard_B2s", "standardBSFamily"},
+		{"Standard_B2ms", "standardBSFamily"},
+		{"Standard_M64ms", "standardMSFamily"},
+		{"Standard_L8s_v2", "standardLSv2Family"},
+		{"Standard_NC6s_v3", "standardNCSv3Family"},
+		{"Standard_NC24ads_A100_v4", "StandardNCADSA100v4Family"},
+		{"Standard_HB60rs", "standardHBSFamily"},
+		{"Standard_HB120rs_v2", "standardHBrsv2Family"},
+		{"Standard_HB120rs_v3", "standardHBrsv3Family"},
+		{"Standard_HC44rs", "standardHCSFamily"},
+		{"Standard_A2_v2", "standardAv2Family"},
+		{"Standard_A1", "standardA0_A7Family"},
+		{"Basic_A1", "basicAFamily"},
+		{"standard_d4s_v3", "standardDSv3Family"},
+	}
+	for _, test := range tests {
+		t.Run(test.size, func(t *testing.T) {
+			if got := azureSkuFamily(test.size); got != test.want {
+				t.Errorf("azureSkuFamily(%q) = %q, want %q", test.size, got, test.want)
+			}
+		})
+	}
+}
@@ -3,9 +3,11 @@ package provider
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/joelpereira/densify-api-client-go"
@@ -24,11 +26,20 @@ func NewDensifyDataSourceCloud() datasource.DataSource {
 
 // densifyDataSource is the data source implementation.
 type densifyDataSourceCloud struct {
-	client *densify.DensifyClient
+	provider *densifyProviderData
 }
 
 // densifyRecoModel maps Densify Recommendation schema data.
 type densifyDataSourceCloudModel struct {
+	// lookup arguments
+	Platform      types.String            `tfsdk:"platform"`
+	AccountNumber types.String            `tfsdk:"account_number"`
+	AccountName   types.String            `tfsdk:"account_name"`
+	ResourceId    types.String            `tfsdk:"resource_id"`
+	Tags          map[string]types.String `tfsdk:"tags"`
+	MatchMode     types.String            `tfsdk:"match_mode"`
+	MatchCount    types.Int64             `tfsdk:"match_count"`
+
 	EntityId            types.String  `tfsdk:"entity_id"`
 	Name                types.String  `tfsdk:"name"`
 	CurrentInstance     types.String  `tfsdk:"current_type"`
@@ -39,6 +50,21 @@ type densifyDataSourceCloudModel struct {
 	ApprovalType        types.String  `tfsdk:"approval_type"`
 	SavingsEstimate     types.Float64 `tfsdk:"savings_estimate"`
 	EffortEstimate      types.String  `tfsdk:"effort_estimate"`
+
+	CurrentCostMonthly      types.Float64 `tfsdk:"current_cost_monthly"`
+	RecommendedCostMonthly  types.Float64 `tfsdk:"recommended_cost_monthly"`
+	CurrentVCPU             types.Int64   `tfsdk:"current_vcpu"`
+	RecommendedVCPU         types.Int64   `tfsdk:"recommended_vcpu"`
+	CurrentMemoryGiB        types.Float64 `tfsdk:"current_memory_gib"`
+	RecommendedMemoryGiB    types.Float64 `tfsdk:"recommended_memory_gib"`
+	PredictedUptime         types.Float64 `tfsdk:"predicted_uptime"`
+	RecommendationFirstSeen types.String  `tfsdk:"recommendation_first_seen"`
+	LastAnalyzed            types.String  `tfsdk:"last_analyzed"`
+
+	AutoApprove          *densifyAutoApproveModel `tfsdk:"auto_approve"`
+	DecisionReason       types.String             `tfsdk:"decision_reason"`
+	MaxRecommendationAge types.String             `tfsdk:"max_recommendation_age"`
+	OnStale              types.String             `tfsdk:"on_stale"`
 }
 
 // Metadata returns the data source type name.
@@ -51,6 +77,31 @@ func (d *densifyDataSourceCloud) Schema(_ context.Context, _ datasource.SchemaRe
 	resp.Schema = schema.Schema{
 		Description: "Fetches a Recommendation for Cloud Compute resources from the Densify API.",
 		Attributes: map[string]schema.Attribute{
+			"platform": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Cloud platform of the compute resource. Defaults to the provider tech_platform. Accepted values are: aws, azure, gcp.",
+			},
+			"account_number": accountNumberSchemaAttribute(),
+			"account_name":   accountNameSchemaAttribute(),
+			"resource_id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Look up the recommendation by cloud resource ID instead of the provider system_name. Ex. AWS instance ID or ARN, Azure resource ID, GCP self link.",
+			},
+			"tags": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Look up the recommendation by tags instead of the provider system_name. Every key/value pair must match. Can be combined with resource_id.",
+			},
+			"match_mode": schema.StringAttribute{
+				Optional:    true,
+				Description: "How the provider system_name is matched against the Densify system names. Accepted values are: exact (default), case_insensitive, prefix, regex. A regex must match the whole name.",
+			},
+			"match_count": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of Densify recommendations that matched the lookup.",
+			},
 			"entity_id": schema.StringAttribute{
 				Computed:    true,
 				Description: "Unique identifier for cloud resource.",
@@ -91,6 +142,74 @@ func (d *densifyDataSourceCloud) Schema(_ context.Context, _ datasource.SchemaRe
 				Computed:    true,
 				Description: "Estimated effort required by applying optimization recommendation. Ex. none, low, med, high.",
 			},
+			"current_cost_monthly": schema.Float64Attribute{
+				Computed:    true,
+				Description: "Estimated monthly cost of the current instance type.",
+			},
+			"recommended_cost_monthly": schema.Float64Attribute{
+				Computed:    true,
+				Description: "Estimated monthly cost of the recommended instance type.",
+			},
+			"current_vcpu": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of vCPUs of the current instance type.",
+			},
+			"recommended_vcpu": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of vCPUs of the recommended instance type.",
+			},
+			"current_memory_gib": schema.Float64Attribute{
+				Computed:    true,
+				Description: "Memory of the current instance type (in gibibytes or GiB).",
+			},
+			"recommended_memory_gib": schema.Float64Attribute{
+				Computed:    true,
+				Description: "Memory of the recommended instance type (in gibibytes or GiB).",
+			},
+			"predicted_uptime": schema.Float64Attribute{
+				Computed:    true,
+				Description: "Predicted uptime (percentage of hours running) used by Densify when estimating costs.",
+			},
+			"recommendation_first_seen": schema.StringAttribute{
+				Computed:    true,
+				Description: "When Densify first generated this recommendation (RFC 3339 timestamp).",
+			},
+			"last_analyzed": schema.StringAttribute{
+				Computed:    true,
+				Description: "When a Densify analysis last produced the recommendation: its last-seen date (recommLastSeen, RFC 3339 timestamp). The API client returns no separate analysis run date.",
+			},
+			"decision_reason": schema.StringAttribute{
+				Computed:    true,
+				Description: "Explains why approved_type was, or was not, set to the recommended instance type.",
+			},
+			"max_recommendation_age": schema.StringAttribute{
+				Optional:    true,
+				Description: "Maximum age of the recommendation before it is considered stale, measured from its last-seen date (last_analyzed, the recommLastSeen field of the API) rather than the date of the analysis run. Recommendations without a last-seen date are stale too. Ex. 30d, 720h.",
+			},
+			"on_stale": schema.StringAttribute{
+				Optional:    true,
+				Description: "What to do when the recommendation is older than max_recommendation_age. Accepted values are: fallback (default), warn, error.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"auto_approve": schema.SingleNestedBlock{
+				Description: "Terraform-side approval policy. At least one threshold must be set. When every configured threshold is met, approved_type is set to the recommended instance type even if it has not been approved in Densify.",
+				Attributes: map[string]schema.Attribute{
+					"max_effort": schema.StringAttribute{
+						Optional:    true,
+						Description: "Highest effort estimate that may be auto-approved. Accepted values are: none, very low, low, medium, high, very high.",
+					},
+					"min_savings": schema.Float64Attribute{
+						Optional:    true,
+						Description: "Estimated monthly savings that must be exceeded to auto-approve the recommendation. Savings equal to min_savings are not auto-approved.",
+					},
+					"optimization_types": schema.ListAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+						Description: "Optimization types that may be auto-approved. Ex. Downsize, Modernize. All types are allowed when omitted.",
+					},
+				},
+			},
 		},
 	}
 }
@@ -102,72 +221,290 @@ func (d *densifyDataSourceCloud) Configure(ctx context.Context, req datasource.C
 		return
 	}
 
-	client, ok := req.ProviderData.(*densify.DensifyClient)
+	providerData, ok := req.ProviderData.(*densifyProviderData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *densify.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *densifyProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = client
+	d.provider = providerData
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (d *densifyDataSourceCloud) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	tflog.Trace(ctx, "Reading Densify API client")
+	ctx, span := startSpan(ctx, "densify_cloud.Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
 	var state densifyDataSourceCloudModel
 
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	source, ok := d.provider.readSource(ctx, req, resp, cloudAttributes)
+	if !ok {
+		return
+	}
+
+	if state.AutoApprove != nil {
+		if err := state.AutoApprove.Validate(); err != nil {
+			attribute := path.Root("auto_approve")
+			if state.AutoApprove.hasThresholds() {
+				attribute = attribute.AtName("max_effort")
+			}
+			resp.Diagnostics.AddAttributeError(
+				attribute,
+				"Invalid Auto Approve Policy",
+				err.Error(),
+			)
+			return
+		}
+	}
+	staleness, diags := newStalenessPolicy(state.MaxRecommendationAge, state.OnStale)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	mode, err := matchMode(state.MatchMode)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("match_mode"),
+			"Invalid Match Mode",
+			err.Error(),
+		)
+		return
+	}
+
+	platform, err := cloudPlatform(&d.provider.settings, state.Platform)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("platform"),
+			"Invalid Cloud Platform",
+			err.Error(),
+		)
+		return
+	}
+	state.Platform = types.StringValue(platform)
+
+	client, err := source.WithQuery(func(query *densify.DensifyAPIQuery) {
+		query.AnalysisTechnology = platform
+		setAccount(query, state.AccountNumber, state.AccountName)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create Densify query",
+			"Densify Client Query Error: "+err.Error(),
+		)
+		return
+	}
+
 	tflog.Debug(ctx, "Densify API client: calling GetAccountOrCluster")
-	_, err := d.client.GetAccountOrCluster()
+	client, _, err = d.provider.accounts.resolve(ctx, client)
 	if err != nil {
-		if d.client.Query.SkipErrors {
+		if client.Query().SkipErrors {
 			// skip the error message
 			return
 		}
 		resp.Diagnostics.AddError(
 			"Unable to Find Densify Account Number/Name",
-			err.Error(),
+			err.Error()+d.provider.settings.describeAccount(state.AccountNumber, state.AccountName),
 		)
 		return
 	}
 	tflog.Trace(ctx, "Densify API client: GetAccountOrCluster: success")
-	tflog.Debug(ctx, "Densify API client: calling GetDensifyRecommendation")
-	reco, err := d.client.GetDensifyRecommendation()
-	if err != nil {
+	systemName := client.Query().SystemName
+	byName := state.ResourceId.IsNull() && len(state.Tags) == 0
+	if byName && systemName == "" {
 		resp.Diagnostics.AddError(
-			"Unable to Find Densify Recommendation",
-			err.Error(),
+			"Missing Densify System Name",
+			"Set system_name in the provider configuration (or the DENSIFY_SYSTEM_NAME environment variable), or set resource_id or tags on the data source."+
+				d.provider.settings.explain("system_name"),
 		)
 		return
 	}
-	tflog.Trace(ctx, "Densify API client: GetDensifyRecommendation: success")
 
-	// if we didn't get a recommendation, return an empty one (instead of nil)
-	if reco == nil {
-		reco = &densify.DensifyRecommendation{}
+	var reco *densify.DensifyRecommendation
+	if !byName || mode != matchModeExact {
+		matchName, err := nameMatcher(mode, systemName)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("match_mode"),
+				"Invalid Match Mode",
+				err.Error(),
+			)
+			return
+		}
+		tflog.Debug(ctx, "Densify API client: calling GetDensifyRecommendations")
+		recos, err := listRecommendations(client)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Find Densify Recommendation",
+				err.Error(),
+			)
+			return
+		}
+		tflog.Trace(ctx, "Densify API client: GetDensifyRecommendations: success")
+
+		matches := filterRecommendations(recos, func(reco *densify.DensifyRecommendation) bool {
+			if byName {
+				return matchName(reco.Name)
+			}
+			return state.matches(reco)
+		})
+		tflog.Debug(ctx, fmt.Sprintf(`Num of matching cloud recommendations: %d`, len(matches)))
+		state.MatchCount = types.Int64Value(int64(len(matches)))
+		if len(matches) > 1 {
+			resp.Diagnostics.AddError(
+				"Ambiguous Densify Recommendation",
+				fmt.Sprintf("Found %d recommendations matching the lookup: %s. Set resource_id, add tags or set account_number to select a single one.", len(matches), ambiguousMatches(matches)),
+			)
+			return
+		}
+		if len(matches) == 0 {
+			if client.Query().SkipErrors {
+				return
+			}
+			detail := "No recommendation matches the resource_id and tags lookup."
+			if byName {
+				detail = fmt.Sprintf("No recommendation matches system_name %q (match_mode %s).", systemName, mode) + didYouMean(systemName, recommendationNames(recos))
+			}
+			resp.Diagnostics.AddError(
+				"Unable to Find Densify Recommendation",
+				detail,
+			)
+			return
+		}
+		reco = &matches[0]
+	} else {
+		tflog.Debug(ctx, "Densify API client: calling GetDensifyRecommendation")
+		reco, err = client.Recommendation()
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Find Densify Recommendation",
+				err.Error()+suggestNames(ctx, client, systemName)+d.provider.settings.explain("system_name"),
+			)
+			return
+		}
+		tflog.Trace(ctx, "Densify API client: GetDensifyRecommendation: success")
+
+		// if we didn't get a recommendation, return an empty one (instead of nil)
+		if reco == nil {
+			reco = &densify.DensifyRecommendation{}
+		}
+		if reco.EntityId != "" {
+			state.MatchCount = types.Int64Value(1)
+		} else {
+			state.MatchCount = types.Int64Value(0)
+			if hint := suggestNames(ctx, client, systemName); hint != "" {
+				resp.Diagnostics.AddWarning(
+					"Densify Recommendation Not Found",
+					fmt.Sprintf("No recommendation was found for system_name %q.", systemName)+hint,
+				)
+			}
+		}
 	}
 
+	result := sourceResult(client)
 	if reco != nil {
+		if reco.EntityId == "" {
+			result = resultFallback
+		}
+
 		// Map response body to model
 		state.EntityId = types.StringValue(reco.EntityId)
 		state.Name = types.StringValue(reco.Name)
+		state.ResourceId = types.StringValue(reco.ResourceId)
 		state.CurrentInstance = types.StringValue(reco.CurrentType)
 		state.RecommendedInstance = types.StringValue(reco.RecommendedType)
-		state.ApprovedInstance = types.StringValue(reco.ApprovedType)
+		state.ApprovedInstance = types.StringValue(client.ApprovedType(reco))
 		state.OptimizationType = types.StringValue(reco.RecommendationType)
 		state.AccountRef = types.StringValue(reco.AccountIdRef)
 		state.ApprovalType = types.StringValue(reco.ApprovalType)
 		state.SavingsEstimate = types.Float64Value(float64(reco.SavingsEstimate))
 		state.EffortEstimate = types.StringValue(reco.EffortEstimate)
+
+		state.CurrentCostMonthly = types.Float64Value(float64(reco.CurrentCost))
+		state.RecommendedCostMonthly = types.Float64Value(float64(reco.RecommendedCost))
+		state.CurrentVCPU = types.Int64Value(int64(reco.CurrentCpu))
+		state.RecommendedVCPU = types.Int64Value(int64(reco.RecommendedCpu))
+		state.CurrentMemoryGiB = types.Float64Value(float64(reco.CurrentMemory))
+		state.RecommendedMemoryGiB = types.Float64Value(float64(reco.RecommendedMemory))
+		state.PredictedUptime = types.Float64Value(float64(reco.PredictedUptime))
+		state.RecommendationFirstSeen = timestampValue(reco.RecommFirstSeen)
+		// the client has no analysis run date: the last-seen date is refreshed by each analysis that produces it.
+		state.LastAnalyzed = timestampValue(reco.RecommLastSeen)
+
+		if staleness.Apply(&resp.Diagnostics, reco.RecommLastSeen, time.Now()) {
+			// a stale recommendation is never approved; use the fallback instance (or keep the current one).
+			fallback := client.Query().FallbackInstance
+			if fallback == "" {
+				fallback = reco.CurrentType
+			}
+			state.ApprovedInstance = types.StringValue(fallback)
+			state.DecisionReason = types.StringValue("Not approved: recommendation is older than max_recommendation_age or has no last-seen date.")
+			result = resultFallback
+		} else {
+			approved, reason := state.AutoApprove.Evaluate(reco, client.ApprovedType(reco))
+			if approved {
+				state.ApprovedInstance = types.StringValue(reco.RecommendedType)
+			}
+			state.DecisionReason = types.StringValue(reason)
+			tflog.Debug(ctx, "Densify approval decision", map[string]any{"approved": approved, "reason": reason})
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
+	recordResult(ctx, result)
+
 	// Set state
-	diags := resp.State.Set(ctx, &state)
+	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 }
+
+// listRecommendations returns the recommendations of the client account, regardless of the system_name.
+func listRecommendations(client RecommendationSource) ([]densify.DensifyRecommendation, error) {
+	client, err := client.WithQuery(func(query *densify.DensifyAPIQuery) {
+		query.SystemName = ""
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client.Recommendations()
+}
+
+// suggestNames returns a did-you-mean hint with the system names of the account closest to name, or an empty string.
+func suggestNames(ctx context.Context, client RecommendationSource, name string) string {
+	recos, err := listRecommendations(client)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to list Densify recommendations for suggestions: "+err.Error())
+		return ""
+	}
+	return didYouMean(name, recommendationNames(recos))
+}
+
+// matches reports whether a recommendation matches the resource_id and tags lookup arguments.
+func (config *densifyDataSourceCloudModel) matches(reco *densify.DensifyRecommendation) bool {
+	if !config.ResourceId.IsNull() && !matchesResourceID(reco, config.ResourceId.ValueString()) {
+		return false
+	}
+	return matchesTags(reco, config.Tags)
+}
+
+// timestampValue converts a Densify API timestamp (milliseconds since epoch) to an RFC 3339 string, or null if it was not set.
+func timestampValue(epochMillis int64) types.String {
+	if epochMillis <= 0 {
+		return types.StringNull()
+	}
+	return types.StringValue(time.UnixMilli(epochMillis).UTC().Format(time.RFC3339))
+}
@@ -3,9 +3,14 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/joelpereira/densify-api-client-go"
@@ -24,7 +29,7 @@ func NewDensifyDataSourceContainer() datasource.DataSource {
 
 // densifyDataSource is the data source implementation.
 type densifyDataSourceContainer struct {
-	client *densify.DensifyClient
+	provider *densifyProviderData
 }
 
 // densifyRecoModel maps coffees schema data.
@@ -42,11 +47,27 @@ type densifyDataSourcePodModel struct {
 	// ApprovalType   types.String `tfsdk:"approval_type"`
 	ContainerCount types.Int64 `tfsdk:"container_count"`
 
-	Containers map[string]densifyDataSourceContainerModel `tfsdk:"containers"`
+	// lookup arguments
+	MatchLabels    map[string]types.String     `tfsdk:"match_labels"`
+	OwnerReference *densifyOwnerReferenceModel `tfsdk:"owner_reference"`
+	MatchMode      types.String                `tfsdk:"match_mode"`
+
+	LastAnalyzed         types.String `tfsdk:"last_analyzed"`
+	MaxRecommendationAge types.String `tfsdk:"max_recommendation_age"`
+	OnStale              types.String `tfsdk:"on_stale"`
+
+	ExcludeContainers []types.String `tfsdk:"exclude_containers"`
+
+	HPA *densifyDataSourceHPAModel `tfsdk:"hpa"`
+
+	Containers     map[string]densifyDataSourceContainerModel `tfsdk:"containers"`
+	InitContainers map[string]densifyDataSourceContainerModel `tfsdk:"init_containers"`
+	Sidecars       map[string]densifyDataSourceContainerModel `tfsdk:"sidecars"`
 }
 
 type densifyDataSourceContainerModel struct {
 	ContainerName    types.String `tfsdk:"container_name"`
+	ContainerKind    types.String `tfsdk:"container_kind"`
 	OptimizationType types.String `tfsdk:"optimization_type"`
 
 	CurCPUReq types.String `tfsdk:"current_cpu_request"`
@@ -58,6 +79,24 @@ type densifyDataSourceContainerModel struct {
 	RecCPULim types.String `tfsdk:"recommended_cpu_limit"`
 	RecMemReq types.String `tfsdk:"recommended_mem_request"`
 	RecMemLim types.String `tfsdk:"recommended_mem_limit"`
+
+	CurEphReq types.String `tfsdk:"current_ephemeral_storage_request"`
+	CurEphLim types.String `tfsdk:"current_ephemeral_storage_limit"`
+	RecEphReq types.String `tfsdk:"recommended_ephemeral_storage_request"`
+	RecEphLim types.String `tfsdk:"recommended_ephemeral_storage_limit"`
+
+	ExtendedResources map[string]densifyDataSourceExtendedResourceModel `tfsdk:"extended_resources"`
+
+	ResourcesRequests map[string]types.String `tfsdk:"resources_requests"`
+	ResourcesLimits   map[string]types.String `tfsdk:"resources_limits"`
+}
+
+// densifyDataSourceExtendedResourceModel maps an extended resource (ex. nvidia.com/gpu) of a container.
+type densifyDataSourceExtendedResourceModel struct {
+	CurrentRequest     types.Int64 `tfsdk:"current_request"`
+	CurrentLimit       types.Int64 `tfsdk:"current_limit"`
+	RecommendedRequest types.Int64 `tfsdk:"recommended_request"`
+	RecommendedLimit   types.Int64 `tfsdk:"recommended_limit"`
 }
 
 // Metadata returns the data source type name.
@@ -106,58 +145,157 @@ func (d *densifyDataSourceContainer) Schema(_ context.Context, _ datasource.Sche
 			},
 			"container_count": schema.Int64Attribute{
 				Computed:    true,
-				Description: "The number of containers within the pod recommendation.",
+				Description: "The number of containers within the pod recommendation, not counting exclude_containers.",
+			},
+			"match_labels": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Look up the controller by labels instead of the provider controller_type and pod_name. Every key/value pair must match, like a Kubernetes label selector.",
+			},
+			"owner_reference": ownerReferenceSchemaAttribute(),
+			"match_mode": schema.StringAttribute{
+				Optional:    true,
+				Description: "How the provider pod_name is matched against the Densify controller names. Accepted values are: exact (default), case_insensitive, prefix, regex. A regex must match the whole name.",
+			},
+			"last_analyzed": schema.StringAttribute{
+				Computed:    true,
+				Description: "When a Densify analysis last produced the recommendation of the pod: its last-seen date (recommLastSeen, RFC 3339 timestamp). The API client returns no separate analysis run date.",
 			},
+			"max_recommendation_age": schema.StringAttribute{
+				Optional:    true,
+				Description: "Maximum age of the recommendation before it is considered stale, measured from its last-seen date (last_analyzed, the recommLastSeen field of the API) rather than the date of the analysis run. Recommendations without a last-seen date are stale too. Ex. 30d, 720h.",
+			},
+			"on_stale": schema.StringAttribute{
+				Optional:    true,
+				Description: "What to do when the recommendation is older than max_recommendation_age. Accepted values are: fallback (default), warn, error.",
+			},
+			"exclude_containers": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Names of containers to leave out of the outputs. Ex. injected sidecars such as istio-proxy.",
+			},
+			"hpa": hpaSchemaAttribute(),
 
 			// nested/multiple container recommendations
 			// https://developer.hashicorp.com/terraform/plugin/framework/handling-data/attributes/map-nested
 			"containers": schema.MapNestedAttribute{
+				NestedObject: containerNestedObject(),
+				Computed:     true,
+				Description:  "Recommendations for the application containers of the pod, keyed by container name.",
+			},
+			"init_containers": schema.MapNestedAttribute{
+				NestedObject: containerNestedObject(),
+				Computed:     true,
+				Description:  "Recommendations for the init containers of the pod, keyed by container name.",
+			},
+			"sidecars": schema.MapNestedAttribute{
+				NestedObject: containerNestedObject(),
+				Computed:     true,
+				Description:  "Recommendations for the injected sidecar containers of the pod (ex. istio-proxy), keyed by container name. Containers are recognized as sidecars by name only when the pod has another application container; use exclude_containers for other sidecars.",
+			},
+		},
+	}
+}
+
+// containerNestedObject defines the attributes of a single container recommendation.
+func containerNestedObject() schema.NestedAttributeObject {
+	return schema.NestedAttributeObject{
+		Attributes: map[string]schema.Attribute{
+			"container_name": schema.StringAttribute{
+				Computed:    true,
+				Description: "The Kubernetes container name.",
+			},
+			"container_kind": schema.StringAttribute{
+				Computed:    true,
+				Description: "The kind of container. Ex. app, init, sidecar.",
+			},
+			"optimization_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Type of optimization. Ex. Downsize, Upsize, Resize, Terminate, etc.",
+			},
+			"current_cpu_request": schema.StringAttribute{
+				Computed:    true,
+				Description: "The current CPU Request for resources (in millicores or m).",
+			},
+			"current_cpu_limit": schema.StringAttribute{
+				Computed:    true,
+				Description: "The current CPU Limit for resources (in millicores or m).",
+			},
+			"current_mem_request": schema.StringAttribute{
+				Computed:    true,
+				Description: "The current Memory Request for resources (in mebibytes or Mi).",
+			},
+			"current_mem_limit": schema.StringAttribute{
+				Computed:    true,
+				Description: "The current Memory Limit for resources (in mebibytes or Mi).",
+			},
+
+			"recommended_cpu_request": schema.StringAttribute{
+				Computed:    true,
+				Description: "The recommended CPU Request for resources (in millicores or m). Empty when Densify has no recommendation and no fallback value is set.",
+			},
+			"recommended_cpu_limit": schema.StringAttribute{
+				Computed:    true,
+				Description: "The recommended CPU Limit for resources (in millicores or m). Empty when Densify has no recommendation and no fallback value is set.",
+			},
+			"recommended_mem_request": schema.StringAttribute{
+				Computed:    true,
+				Description: "The recommended Memory Request for resources (in mebibytes or Mi). Empty when Densify has no recommendation and no fallback value is set.",
+			},
+			"recommended_mem_limit": schema.StringAttribute{
+				Computed:    true,
+				Description: "The recommended Memory Limit for resources (in mebibytes or Mi). Empty when Densify has no recommendation and no fallback value is set.",
+			},
+
+			"current_ephemeral_storage_request": schema.StringAttribute{
+				Computed:    true,
+				Description: "The current ephemeral-storage Request for resources (in mebibytes or Mi). Null when not set.",
+			},
+			"current_ephemeral_storage_limit": schema.StringAttribute{
+				Computed:    true,
+				Description: "The current ephemeral-storage Limit for resources (in mebibytes or Mi). Null when not set.",
+			},
+			"recommended_ephemeral_storage_request": schema.StringAttribute{
+				Computed:    true,
+				Description: "The recommended ephemeral-storage Request for resources (in mebibytes or Mi). Null when not set.",
+			},
+			"recommended_ephemeral_storage_limit": schema.StringAttribute{
+				Computed:    true,
+				Description: "The recommended ephemeral-storage Limit for resources (in mebibytes or Mi). Null when not set.",
+			},
+			"extended_resources": schema.MapNestedAttribute{
+				Computed:    true,
+				Description: "Extended resources of the container, keyed by resource name. Ex. nvidia.com/gpu. Null when the container has none.",
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
-						"container_name": schema.StringAttribute{
+						"current_request": schema.Int64Attribute{
 							Computed:    true,
-							Description: "The Kubernetes container name.",
+							Description: "The current Request for the extended resource.",
 						},
-						"optimization_type": schema.StringAttribute{
+						"current_limit": schema.Int64Attribute{
 							Computed:    true,
-							Description: "Type of optimization. Ex. Downsize, Upsize, Resize, Terminate, etc.",
+							Description: "The current Limit for the extended resource.",
 						},
-						"current_cpu_request": schema.StringAttribute{
+						"recommended_request": schema.Int64Attribute{
 							Computed:    true,
-							Description: "The current CPU Request for resources (in millicores or m).",
+							Description: "The recommended Request for the extended resource.",
 						},
-						"current_cpu_limit": schema.StringAttribute{
+						"recommended_limit": schema.Int64Attribute{
 							Computed:    true,
-							Description: "The current CPU Limit for resources (in millicores or m).",
-						},
-						"current_mem_request": schema.StringAttribute{
-							Computed:    true,
-							Description: "The current Memory Request for resources (in mebibytes or Mi).",
-						},
-						"current_mem_limit": schema.StringAttribute{
-							Computed:    true,
-							Description: "The current Memory Limit for resources (in mebibytes or Mi).",
-						},
-
-						"recommended_cpu_request": schema.StringAttribute{
-							Computed:    true,
-							Description: "The recommended CPU Request for resources (in millicores or m).",
-						},
-						"recommended_cpu_limit": schema.StringAttribute{
-							Computed:    true,
-							Description: "The recommended CPU Limit for resources (in millicores or m).",
-						},
-						"recommended_mem_request": schema.StringAttribute{
-							Computed:    true,
-							Description: "The recommended Memory Request for resources (in mebibytes or Mi).",
-						},
-						"recommended_mem_limit": schema.StringAttribute{
-							Computed:    true,
-							Description: "The recommended Memory Limit for resources (in mebibytes or Mi).",
+							Description: "The recommended Limit for the extended resource.",
 						},
 					},
 				},
-				Computed: true,
+			},
+			"resources_requests": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "The recommended Requests in the shape of the kubernetes provider resources.requests map. Ex. cpu, memory, ephemeral-storage, nvidia.com/gpu. Unset and zero values are omitted.",
+			},
+			"resources_limits": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "The recommended Limits in the shape of the kubernetes provider resources.limits map. Ex. cpu, memory, ephemeral-storage, nvidia.com/gpu. Unset and zero values are omitted.",
 			},
 		},
 	}
@@ -170,58 +308,125 @@ func (d *densifyDataSourceContainer) Configure(ctx context.Context, req datasour
 		return
 	}
 
-	client, ok := req.ProviderData.(*densify.DensifyClient)
+	providerData, ok := req.ProviderData.(*densifyProviderData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *densify.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *densifyProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = client
+	d.provider = providerData
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (d *densifyDataSourceContainer) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	tflog.Trace(ctx, "Reading Densify API client")
+	ctx, span := startSpan(ctx, "densify_container.Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
 	var state densifyDataSourcePodModel
 
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	source, ok := d.provider.readSource(ctx, req, resp, k8sAttributes)
+	if !ok {
+		return
+	}
+
+	staleness, diags := newStalenessPolicy(state.MaxRecommendationAge, state.OnStale)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	mode, err := matchMode(state.MatchMode)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("match_mode"),
+			"Invalid Match Mode",
+			err.Error(),
+		)
+		return
+	}
+
+	client, err := k8sClient(source, &d.provider.settings)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create Densify query",
+			"Densify Client Query Error: "+err.Error(),
+		)
+		return
+	}
+
 	tflog.Debug(ctx, "Densify API client: calling GetAccountOrCluster")
-	_, err := d.client.GetAccountOrCluster()
+	client, _, err = d.provider.accounts.resolve(ctx, client)
 	if err != nil {
-		if d.client.Query.SkipErrors {
+		if client.Query().SkipErrors {
 			// skip the error message
 			return
 		}
 		resp.Diagnostics.AddError(
 			"Unable to Find Densify Account Number/Name",
-			err.Error(),
+			err.Error()+d.provider.settings.explain("cluster"),
 		)
 		return
 	}
 	tflog.Trace(ctx, "Densify API client: GetAccountOrCluster: success")
-	tflog.Debug(ctx, "Densify API client: calling GetDensifyRecommendation")
-	podReco, err := d.client.GetDensifyRecommendation()
-	if err != nil {
+	var podReco *densify.DensifyRecommendation
+	byPodName := len(state.MatchLabels) == 0 && state.OwnerReference == nil
+	if byPodName && (client.Query().K8sControllerType == "" || client.Query().K8sPodName == "") {
 		resp.Diagnostics.AddError(
-			"Unable to Find Densify Recommendation",
-			err.Error(),
+			"Missing Kubernetes Controller",
+			"Set controller_type and pod_name in the provider configuration, or set match_labels or owner_reference on the data source."+
+				d.provider.settings.explain("controller_type", "pod_name"),
 		)
 		return
 	}
-	tflog.Trace(ctx, "Densify API client: GetDensifyRecommendation: success")
+	if !byPodName || mode != matchModeExact {
+		podReco = lookupController(ctx, client, &state, mode, resp)
+		if podReco == nil {
+			return
+		}
+	} else {
+		tflog.Debug(ctx, "Densify API client: calling GetDensifyRecommendation")
+		podReco, err = client.Recommendation()
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Find Densify Recommendation",
+				err.Error()+suggestControllers(ctx, client, client.Query().K8sPodName)+
+					d.provider.settings.explain("cluster", "namespace", "controller_type", "pod_name"),
+			)
+			return
+		}
+		tflog.Trace(ctx, "Densify API client: GetDensifyRecommendation: success")
+		if podReco == nil {
+			if hint := suggestControllers(ctx, client, client.Query().K8sPodName); hint != "" {
+				resp.Diagnostics.AddWarning(
+					"Densify Recommendation Not Found",
+					fmt.Sprintf("No recommendation was found for pod_name %q.", client.Query().K8sPodName)+hint,
+				)
+			}
+		}
+	}
 
 	// if we didn't get a recommendation, return an empty one (instead of nil)
 	if podReco == nil {
 		podReco = &densify.DensifyRecommendation{}
 	}
 
+	result := sourceResult(client)
 	if podReco != nil {
+		if podReco.EntityId == "" {
+			result = resultFallback
+		}
+
 		// Map response body to model
 		state.EntityId = types.StringValue(podReco.EntityId)
-		state.Name = types.StringValue(podReco.Name)
 		state.AccountRef = types.StringValue(podReco.AccountIdRef)
 		// state.OptimizationType = types.StringValue(podReco.RecommendationType)
 		// state.ApprovalType = types.StringValue(podReco.ApprovalType)
@@ -231,56 +436,394 @@ func (d *densifyDataSourceContainer) Read(ctx context.Context, req datasource.Re
 		state.Namespace = types.StringValue(podReco.Namespace)
 		state.ControllerType = types.StringValue(podReco.ControllerType)
 		state.PodName = types.StringValue(podReco.PodService)
+		// the client has no analysis run date: the last-seen date is refreshed by each analysis that produces it.
+		state.LastAnalyzed = timestampValue(podReco.RecommLastSeen)
 
-		cpuUnit := "m"  // millicores
-		memUnit := "Mi" // mebibytes
+		useFallback := staleness.Apply(&resp.Diagnostics, podReco.RecommLastSeen, time.Now())
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if useFallback {
+			result = resultFallback
+		}
+
+		state.Containers = map[string]densifyDataSourceContainerModel{}
+		state.InitContainers = map[string]densifyDataSourceContainerModel{}
+		state.Sidecars = map[string]densifyDataSourceContainerModel{}
 
-		if state.Containers == nil {
-			state.Containers = map[string]densifyDataSourceContainerModel{}
+		excluded := map[string]bool{}
+		for _, name := range state.ExcludeContainers {
+			excluded[name.ValueString()] = true
 		}
+		containerCount := 0
+
+		// total CPU requests of the pod, used to check the effect of the recommendation on the HPA threshold
+		curCPUReqTotal := 0
+		recCPUReqTotal := 0
 
 		// containersMap := map[string]densifyDataSourceContainerModel{}
 		tflog.Debug(ctx, fmt.Sprintf(`Num of Containers: %d`, len(podReco.Containers)))
 		for i := 0; i < len(podReco.Containers); i++ {
 			reco := podReco.Containers[i]
-			c := densifyDataSourceContainerModel{}
-			c.ContainerName = types.StringValue(reco.Container)
-			if reco.Container != "" {
-				state.Name = types.StringValue(reco.Container)
+			kind := containerKind(podReco, &reco)
+
+			// excluded containers still run in the pod, so they count towards the HPA CPU requests
+			if kind != containerKindInit {
+				curCPUReqTotal += reco.CurrentCpuRequest
+				if !useFallback && reco.RecommendedCpuRequest > 0 {
+					recCPUReqTotal += reco.RecommendedCpuRequest
+				} else {
+					recCPUReqTotal += reco.CurrentCpuRequest
+				}
+			}
+			if excluded[reco.Container] {
+				tflog.Debug(ctx, fmt.Sprintf(`Excluding container: %s`, reco.Container))
+				continue
 			}
-			c.OptimizationType = types.StringValue(reco.RecommendationType)
-
-			c.CurCPUReq = types.StringValue(fmt.Sprintf(`%d%s`, reco.CurrentCpuRequest, cpuUnit))
-			c.CurCPULim = types.StringValue(fmt.Sprintf(`%d%s`, reco.CurrentCpuLimit, cpuUnit))
-			c.CurMemReq = types.StringValue(fmt.Sprintf(`%d%s`, reco.CurrentMemRequest, memUnit))
-			c.CurMemLim = types.StringValue(fmt.Sprintf(`%d%s`, reco.CurrentMemLimit, memUnit))
-
-			if reco.RecommendedCpuRequest > 0 || reco.RecommendedMemRequest > 0 {
-				c.RecCPUReq = types.StringValue(fmt.Sprintf(`%d%s`, reco.RecommendedCpuRequest, cpuUnit))
-				c.RecCPULim = types.StringValue(fmt.Sprintf(`%d%s`, reco.RecommendedCpuLimit, cpuUnit))
-				c.RecMemReq = types.StringValue(fmt.Sprintf(`%d%s`, reco.RecommendedMemRequest, memUnit))
-				c.RecMemLim = types.StringValue(fmt.Sprintf(`%d%s`, reco.RecommendedMemLimit, memUnit))
-			} else {
-				// if there are no recommendations, take the fallback values and output them as recommended
-				c.RecCPUReq = types.StringValue(reco.FallbackCpuRequest)
-				c.RecCPULim = types.StringValue(reco.FallbackCpuLimit)
-				c.RecMemReq = types.StringValue(reco.FallbackMemRequest)
-				c.RecMemLim = types.StringValue(reco.FallbackMemLimit)
+			containerCount++
+
+			c := newContainerModel(&reco, kind, useFallback)
+
+			// add the container to the map for its kind
+			switch kind {
+			case containerKindInit:
+				state.InitContainers[reco.Container] = c
+			case containerKindSidecar:
+				state.Sidecars[reco.Container] = c
+			default:
+				state.Containers[reco.Container] = c
 			}
+		}
+		state.ContainerCount = types.Int64Value(int64(containerCount))
+		state.Name = types.StringValue(podContainerName(podReco, excluded))
 
-			// add the container to the map of containers
-			state.Containers[reco.Container] = c
+		state.HPA = newHPAModel(podReco)
+		if warning, ok := hpaThresholdWarning(podReco, curCPUReqTotal, recCPUReqTotal); ok {
+			resp.Diagnostics.AddWarning("HPA Threshold Shift", warning)
 		}
 	}
 
-	// now we can set the count of containers
-	state.ContainerCount = types.Int64Value(int64(len(podReco.Containers)))
+	recordResult(ctx, result)
 
 	// Set state
-	diags := resp.State.Set(ctx, &state)
+	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		tflog.Trace(ctx, fmt.Sprintf(`Errors: %s`, resp.Diagnostics.Errors()))
 		return
 	}
 }
+
+// listControllers returns the recommendations of the controllers in the namespace of the client query.
+func listControllers(client RecommendationSource) ([]densify.DensifyRecommendation, error) {
+	client, err := client.WithQuery(func(query *densify.DensifyAPIQuery) {
+		query.K8sControllerType = ""
+		query.K8sPodName = ""
+		query.K8sContainerName = ""
+	})
+	if err != nil {
+		return nil, err
+	}
+	recos, err := client.Recommendations()
+	if err != nil {
+		return nil, err
+	}
+	namespace := client.Query().K8sNamespace
+	return filterRecommendations(recos, func(reco *densify.DensifyRecommendation) bool {
+		return matchesFilter(reco.Namespace, namespace)
+	}), nil
+}
+
+// suggestControllers returns a did-you-mean hint with the controller names of the namespace closest to name,
+// or an empty string.
+func suggestControllers(ctx context.Context, client RecommendationSource, name string) string {
+	recos, err := listControllers(client)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to list Densify recommendations for suggestions: "+err.Error())
+		return ""
+	}
+	return didYouMean(name, controllerNames(recos))
+}
+
+// lookupController finds the recommendation of the controller selected by the match_labels or owner_reference
+// arguments, or by the provider pod_name with a non exact match_mode, within the provider namespace.
+// It returns nil when the lookup failed, after adding any diagnostics.
+func lookupController(ctx context.Context, client RecommendationSource, state *densifyDataSourcePodModel, mode string, resp *datasource.ReadResponse) *densify.DensifyRecommendation {
+	tflog.Debug(ctx, "Densify API client: calling GetDensifyRecommendations")
+	recos, err := listControllers(client)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Recommendation",
+			err.Error(),
+		)
+		return nil
+	}
+	tflog.Trace(ctx, "Densify API client: GetDensifyRecommendations: success")
+
+	namespace := client.Query().K8sNamespace
+	matches := filterRecommendations(recos, func(reco *densify.DensifyRecommendation) bool {
+		return matchesLabels(reco, state.MatchLabels)
+	})
+	lookup := "match_labels"
+	hint := ""
+	switch {
+	case state.OwnerReference != nil:
+		candidates := matches
+		kind := state.OwnerReference.Kind.ValueString()
+		name := state.OwnerReference.Name.ValueString()
+		lookup = fmt.Sprintf("owner %s %q", kind, name)
+		for _, owner := range ownerCandidates(kind, name) {
+			matches = filterRecommendations(candidates, func(reco *densify.DensifyRecommendation) bool {
+				return isOwner(reco, owner)
+			})
+			if len(matches) > 0 {
+				tflog.Debug(ctx, fmt.Sprintf(`Resolved owner %s %q to %s %q`, kind, name, owner.controllerType, owner.name))
+				break
+			}
+		}
+
+	case len(state.MatchLabels) == 0:
+		podName := client.Query().K8sPodName
+		controllerType := client.Query().K8sControllerType
+		matchName, err := nameMatcher(mode, podName)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("match_mode"),
+				"Invalid Match Mode",
+				err.Error(),
+			)
+			return nil
+		}
+		lookup = fmt.Sprintf("pod_name %q (match_mode %s)", podName, mode)
+		hint = didYouMean(podName, controllerNames(recos))
+		matches = filterRecommendations(recos, func(reco *densify.DensifyRecommendation) bool {
+			return matchesFilter(reco.ControllerType, controllerType) && matchName(reco.PodService)
+		})
+	}
+	tflog.Debug(ctx, fmt.Sprintf(`Num of matching controller recommendations: %d`, len(matches)))
+
+	if len(matches) > 1 {
+		names := []string{}
+		for _, m := range matches {
+			names = append(names, m.ControllerType+"/"+m.PodService)
+		}
+		resp.Diagnostics.AddError(
+			"Ambiguous Densify Recommendation",
+			fmt.Sprintf("Found %d controllers in namespace %q matching %s: %s. Add labels to select a single one.", len(matches), namespace, lookup, strings.Join(names, ", ")),
+		)
+		return nil
+	}
+	if len(matches) == 0 {
+		if !client.Query().SkipErrors {
+			resp.Diagnostics.AddError(
+				"Unable to Find Densify Recommendation",
+				fmt.Sprintf("No controller in namespace %q matches %s.", namespace, lookup)+hint,
+			)
+		}
+		return nil
+	}
+	return &matches[0]
+}
+
+// controllerNames returns the controller names of recommendations.
+func controllerNames(recos []densify.DensifyRecommendation) []string {
+	names := []string{}
+	for i := range recos {
+		names = append(names, recos[i].PodService)
+	}
+	return names
+}
+
+// Kinds of containers within a pod.
+const (
+	containerKindApp     = "app"
+	containerKindInit    = "init"
+	containerKindSidecar = "sidecar"
+)
+
+// knownSidecars lists the names of containers injected by common service meshes and agents.
+var knownSidecars = map[string]bool{
+	"istio-proxy":      true,
+	"linkerd-proxy":    true,
+	"envoy":            true,
+	"envoy-sidecar":    true,
+	"consul-dataplane": true,
+	"vault-agent":      true,
+	"cloudsql-proxy":   true,
+	"cloud-sql-proxy":  true,
+	"datadog-agent":    true,
+	"fluent-bit":       true,
+}
+
+// containerKind classifies a container of a pod as an init container, an injected sidecar or an application
+// container. The kind reported by Densify is used when available. Otherwise a container is only recognized as a
+// sidecar by name when the pod has another application container, since a proxy or agent may also be the
+// application of its pod (ex. an Envoy gateway or a fluent-bit DaemonSet).
+func containerKind(podReco *densify.DensifyRecommendation, reco *densify.DensifyContainerRecommendation) string {
+	if kind := reportedContainerKind(reco); kind != "" {
+		return kind
+	}
+	if !knownSidecars[reco.Container] {
+		return containerKindApp
+	}
+	for i := range podReco.Containers {
+		other := &podReco.Containers[i]
+		if other.Container != reco.Container && reportedContainerKind(other) == "" && !knownSidecars[other.Container] {
+			return containerKindSidecar
+		}
+	}
+	return containerKindApp
+}
+
+// reportedContainerKind returns the kind of a container reported by Densify, or empty if it is not reported.
+func reportedContainerKind(reco *densify.DensifyContainerRecommendation) string {
+	switch strings.ToLower(reco.ContainerType) {
+	case "init", "initcontainer", "init_container":
+		return containerKindInit
+	case "sidecar":
+		return containerKindSidecar
+	}
+	return ""
+}
+
+// podContainerName returns the container name of a pod recommendation: the last app container that is not
+// excluded, or the pod manifest name when the pod has none.
+func podContainerName(podReco *densify.DensifyRecommendation, excluded map[string]bool) string {
+	name := podReco.Name
+	for i := range podReco.Containers {
+		reco := &podReco.Containers[i]
+		if reco.Container != "" && !excluded[reco.Container] && containerKind(podReco, reco) == containerKindApp {
+			name = reco.Container
+		}
+	}
+	return name
+}
+
+// newContainerModel maps a container recommendation. When useFallback is set (the recommendation is stale),
+// the fallback or current values are returned as recommended.
+func newContainerModel(reco *densify.DensifyContainerRecommendation, kind string, useFallback bool) densifyDataSourceContainerModel {
+	cpuUnit := "m"  // millicores
+	memUnit := "Mi" // mebibytes
+
+	c := densifyDataSourceContainerModel{}
+	c.ContainerName = types.StringValue(reco.Container)
+	c.ContainerKind = types.StringValue(kind)
+	c.OptimizationType = types.StringValue(reco.RecommendationType)
+
+	c.CurCPUReq = types.StringValue(fmt.Sprintf(`%d%s`, reco.CurrentCpuRequest, cpuUnit))
+	c.CurCPULim = types.StringValue(fmt.Sprintf(`%d%s`, reco.CurrentCpuLimit, cpuUnit))
+	c.CurMemReq = types.StringValue(fmt.Sprintf(`%d%s`, reco.CurrentMemRequest, memUnit))
+	c.CurMemLim = types.StringValue(fmt.Sprintf(`%d%s`, reco.CurrentMemLimit, memUnit))
+
+	if useFallback {
+		// the recommendation is stale, so use the fallback values (or keep the current ones)
+		c.RecCPUReq = fallbackValue(reco.FallbackCpuRequest, c.CurCPUReq)
+		c.RecCPULim = fallbackValue(reco.FallbackCpuLimit, c.CurCPULim)
+		c.RecMemReq = fallbackValue(reco.FallbackMemRequest, c.CurMemReq)
+		c.RecMemLim = fallbackValue(reco.FallbackMemLimit, c.CurMemLim)
+	} else if hasRecommendation(reco) {
+		c.RecCPUReq = types.StringValue(fmt.Sprintf(`%d%s`, reco.RecommendedCpuRequest, cpuUnit))
+		c.RecCPULim = types.StringValue(fmt.Sprintf(`%d%s`, reco.RecommendedCpuLimit, cpuUnit))
+		c.RecMemReq = types.StringValue(fmt.Sprintf(`%d%s`, reco.RecommendedMemRequest, memUnit))
+		c.RecMemLim = types.StringValue(fmt.Sprintf(`%d%s`, reco.RecommendedMemLimit, memUnit))
+	} else {
+		// if there are no recommendations, take the fallback values and output them as recommended
+		c.RecCPUReq = types.StringValue(reco.FallbackCpuRequest)
+		c.RecCPULim = types.StringValue(reco.FallbackCpuLimit)
+		c.RecMemReq = types.StringValue(reco.FallbackMemRequest)
+		c.RecMemLim = types.StringValue(reco.FallbackMemLimit)
+	}
+
+	c.CurEphReq = quantityValue(reco.CurrentEphemeralStorageRequest, memUnit)
+	c.CurEphLim = quantityValue(reco.CurrentEphemeralStorageLimit, memUnit)
+	c.RecEphReq = c.CurEphReq
+	c.RecEphLim = c.CurEphLim
+	if !useFallback && (reco.RecommendedEphemeralStorageRequest > 0 || reco.RecommendedEphemeralStorageLimit > 0) {
+		c.RecEphReq = quantityValue(reco.RecommendedEphemeralStorageRequest, memUnit)
+		c.RecEphLim = quantityValue(reco.RecommendedEphemeralStorageLimit, memUnit)
+	}
+	c.ExtendedResources = newExtendedResources(reco.ExtendedResources, useFallback)
+	c.ResourcesRequests, c.ResourcesLimits = c.resources()
+
+	return c
+}
+
+// hasRecommendation reports whether Densify has CPU or memory recommendations for a container.
+func hasRecommendation(reco *densify.DensifyContainerRecommendation) bool {
+	return reco.RecommendedCpuRequest > 0 || reco.RecommendedMemRequest > 0
+}
+
+// resources builds the requests and limits maps expected by the kubernetes provider from the recommended values,
+// omitting the ones that are not set.
+func (c *densifyDataSourceContainerModel) resources() (map[string]types.String, map[string]types.String) {
+	requests := map[string]types.String{}
+	limits := map[string]types.String{}
+	addResource(requests, "cpu", c.RecCPUReq)
+	addResource(limits, "cpu", c.RecCPULim)
+	addResource(requests, "memory", c.RecMemReq)
+	addResource(limits, "memory", c.RecMemLim)
+	addResource(requests, "ephemeral-storage", c.RecEphReq)
+	addResource(limits, "ephemeral-storage", c.RecEphLim)
+	for name, r := range c.ExtendedResources {
+		if r.RecommendedRequest.ValueInt64() > 0 {
+			requests[name] = types.StringValue(fmt.Sprintf(`%d`, r.RecommendedRequest.ValueInt64()))
+		}
+		if r.RecommendedLimit.ValueInt64() > 0 {
+			limits[name] = types.StringValue(fmt.Sprintf(`%d`, r.RecommendedLimit.ValueInt64()))
+		}
+	}
+	return requests, limits
+}
+
+// addResource adds a resource quantity to a requests or limits map if it is set. Zero quantities (ex. "0m" when
+// Densify has no limit) are left out, since Kubernetes rejects a limit below the request.
+func addResource(resources map[string]types.String, name string, v types.String) {
+	if v.IsNull() || v.IsUnknown() || v.ValueString() == "" || isZeroQuantity(v.ValueString()) {
+		return
+	}
+	resources[name] = v
+}
+
+// isZeroQuantity reports whether a resource quantity is zero, whatever its unit. Ex. "0m", "0Mi".
+func isZeroQuantity(quantity string) bool {
+	value, err := strconv.ParseFloat(strings.TrimRightFunc(quantity, unicode.IsLetter), 64)
+	return err == nil && value == 0
+}
+
+// quantityValue formats a resource quantity with its unit, or returns null if it is not set.
+func quantityValue(v int, unit string) types.String {
+	if v <= 0 {
+		return types.StringNull()
+	}
+	return types.StringValue(fmt.Sprintf(`%d%s`, v, unit))
+}
+
+// newExtendedResources maps the extended resources of a container, or returns nil if it has none.
+// The current values are kept as recommended when the recommendation is stale or Densify has none.
+func newExtendedResources(resources []densify.DensifyExtendedResource, useCurrent bool) map[string]densifyDataSourceExtendedResourceModel {
+	if len(resources) == 0 {
+		return nil
+	}
+	extended := map[string]densifyDataSourceExtendedResourceModel{}
+	for _, r := range resources {
+		recReq, recLim := r.RecommendedRequest, r.RecommendedLimit
+		if useCurrent || (recReq <= 0 && recLim <= 0) {
+			recReq, recLim = r.CurrentRequest, r.CurrentLimit
+		}
+		extended[r.Name] = densifyDataSourceExtendedResourceModel{
+			CurrentRequest:     types.Int64Value(int64(r.CurrentRequest)),
+			CurrentLimit:       types.Int64Value(int64(r.CurrentLimit)),
+			RecommendedRequest: types.Int64Value(int64(recReq)),
+			RecommendedLimit:   types.Int64Value(int64(recLim)),
+		}
+	}
+	return extended
+}
+
+// fallbackValue returns the fallback value if one was provided, otherwise the current value.
+func fallbackValue(fallback string, current types.String) types.String {
+	if fallback != "" {
+		return types.StringValue(fallback)
+	}
+	return current
+}
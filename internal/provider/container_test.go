@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+func TestPodContainerName(t *testing.T) {
+	pod := &densify.DensifyRecommendation{
+		Name: "web",
+		Containers: []densify.DensifyContainerRecommendation{
+			{Container: "worker"},
+			{Container: "istio-proxy"},
+			{Container: "migrate", ContainerType: "init"},
+			{Container: "api"},
+		},
+	}
+	tests := []struct {
+		name     string
+		pod      *densify.DensifyRecommendation
+		excluded map[string]bool
+		want     string
+	}{
+		{"last app container", pod, nil, "api"},
+		{"excluded container", pod, map[string]bool{"api": true}, "worker"},
+		{"no app containers", &densify.DensifyRecommendation{Name: "job", Containers: []densify.DensifyContainerRecommendation{{Container: "migrate", ContainerType: "init"}}}, nil, "job"},
+		{"no containers", &densify.DensifyRecommendation{Name: "web"}, nil, "web"},
+		{"agent pod", &densify.DensifyRecommendation{Name: "logs", Containers: []densify.DensifyContainerRecommendation{{Container: "fluent-bit"}}}, nil, "fluent-bit"},
+		{"excluded agent pod", &densify.DensifyRecommendation{Name: "logs", Containers: []densify.DensifyContainerRecommendation{{Container: "fluent-bit"}}}, map[string]bool{"fluent-bit": true}, "logs"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := podContainerName(test.pod, test.excluded); got != test.want {
+				t.Errorf("podContainerName() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestContainerKind(t *testing.T) {
+	tests := []struct {
+		name       string
+		containers []densify.DensifyContainerRecommendation
+		want       map[string]string
+	}{
+		{
+			name:       "sidecar by name",
+			containers: []densify.DensifyContainerRecommendation{{Container: "web"}, {Container: "istio-proxy"}},
+			want:       map[string]string{"web": containerKindApp, "istio-proxy": containerKindSidecar},
+		},
+		{
+			name:       "proxy as the application",
+			containers: []densify.DensifyContainerRecommendation{{Container: "envoy"}},
+			want:       map[string]string{"envoy": containerKindApp},
+		},
+		{
+			name:       "agents only",
+			containers: []densify.DensifyContainerRecommendation{{Container: "fluent-bit"}, {Container: "datadog-agent"}, {Container: "setup", ContainerType: "init"}},
+			want:       map[string]string{"fluent-bit": containerKindApp, "datadog-agent": containerKindApp, "setup": containerKindInit},
+		},
+		{
+			name:       "reported kinds",
+			containers: []densify.DensifyContainerRecommendation{{Container: "envoy", ContainerType: "Sidecar"}, {Container: "log-shipper", ContainerType: "sidecar"}, {Container: "migrate", ContainerType: "InitContainer"}},
+			want:       map[string]string{"envoy": containerKindSidecar, "log-shipper": containerKindSidecar, "migrate": containerKindInit},
+		},
+		{
+			name:       "reported sidecar is not the application",
+			containers: []densify.DensifyContainerRecommendation{{Container: "vault-agent"}, {Container: "log-shipper", ContainerType: "sidecar"}},
+			want:       map[string]string{"vault-agent": containerKindApp, "log-shipper": containerKindSidecar},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pod := &densify.DensifyRecommendation{Containers: test.containers}
+			for i := range pod.Containers {
+				c := &pod.Containers[i]
+				if got := containerKind(pod, c); got != test.want[c.Container] {
+					t.Errorf("containerKind(%q) = %q, want %q", c.Container, got, test.want[c.Container])
+				}
+			}
+		})
+	}
+}
+
+func TestContainerResources(t *testing.T) {
+	tests := []struct {
+		name         string
+		reco         densify.DensifyContainerRecommendation
+		useFallback  bool
+		wantRequests map[string]string
+		wantLimits   map[string]string
+	}{
+		{
+			name:         "requests and limits",
+			reco:         densify.DensifyContainerRecommendation{RecommendedCpuRequest: 250, RecommendedCpuLimit: 500, RecommendedMemRequest: 256, RecommendedMemLimit: 512},
+			wantRequests: map[string]string{"cpu": "250m", "memory": "256Mi"},
+			wantLimits:   map[string]string{"cpu": "500m", "memory": "512Mi"},
+		},
+		{
+			name:         "no recommended limits",
+			reco:         densify.DensifyContainerRecommendation{CurrentCpuLimit: 1000, RecommendedCpuRequest: 250, RecommendedMemRequest: 256},
+			wantRequests: map[string]string{"cpu": "250m", "memory": "256Mi"},
+			wantLimits:   map[string]string{},
+		},
+		{
+			name:         "no recommendation",
+			reco:         densify.DensifyContainerRecommendation{CurrentCpuRequest: 100},
+			wantRequests: map[string]string{},
+			wantLimits:   map[string]string{},
+		},
+		{
+			name:         "no recommendation with fallback values",
+			reco:         densify.DensifyContainerRecommendation{CurrentCpuRequest: 100, FallbackCpuRequest: "200m", FallbackMemLimit: "1Gi"},
+			wantRequests: map[string]string{"cpu": "200m"},
+			wantLimits:   map[string]string{"memory": "1Gi"},
+		},
+		{
+			name:         "stale recommendation",
+			reco:         densify.DensifyContainerRecommendation{CurrentCpuRequest: 100, CurrentMemRequest: 128, RecommendedCpuRequest: 250, FallbackMemRequest: "64Mi"},
+			useFallback:  true,
+			wantRequests: map[string]string{"cpu": "100m", "memory": "64Mi"},
+			wantLimits:   map[string]string{},
+		},
+		{
+			name: "ephemeral storage and extended resources",
+			reco: densify.DensifyContainerRecommendation{
+				RecommendedCpuRequest:              250,
+				RecommendedEphemeralStorageRequest: 1024,
+				ExtendedResources:                  []densify.DensifyExtendedResource{{Name: "nvidia.com/gpu", CurrentLimit: 1, RecommendedLimit: 1}},
+			},
+			wantRequests: map[string]string{"cpu": "250m", "ephemeral-storage": "1024Mi"},
+			wantLimits:   map[string]string{"nvidia.com/gpu": "1"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newContainerModel(&test.reco, containerKindApp, test.useFallback)
+			for _, m := range []struct {
+				name string
+				got  map[string]types.String
+				want map[string]string
+			}{
+				{"resources_requests", c.ResourcesRequests, test.wantRequests},
+				{"resources_limits", c.ResourcesLimits, test.wantLimits},
+			} {
+				if len(m.got) != len(m.want) {
+					t.Errorf("%s = %v, want %v", m.name, m.got, m.want)
+					continue
+				}
+				for name, want := range m.want {
+					if got := m.got[name]; !got.Equal(types.StringValue(want)) {
+						t.Errorf("%s[%q] = %s, want %q", m.name, name, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestContainerQuantities(t *testing.T) {
+	// the quantity attributes keep the zero values of Densify, which are only left out of the resources maps.
+	c := newContainerModel(&densify.DensifyContainerRecommendation{CurrentCpuRequest: 100, RecommendedCpuRequest: 250}, containerKindApp, false)
+	tests := []struct {
+		name string
+		got  types.String
+		want string
+	}{
+		{"current_cpu_request", c.CurCPUReq, "100m"},
+		{"current_cpu_limit", c.CurCPULim, "0m"},
+		{"current_mem_limit", c.CurMemLim, "0Mi"},
+		{"recommended_cpu_request", c.RecCPUReq, "250m"},
+		{"recommended_cpu_limit", c.RecCPULim, "0m"},
+		{"recommended_mem_request", c.RecMemReq, "0Mi"},
+	}
+	for _, test := range tests {
+		if !test.got.Equal(types.StringValue(test.want)) {
+			t.Errorf("%s = %s, want %q", test.name, test.got, test.want)
+		}
+	}
+
+	for quantity, want := range map[string]bool{"0m": true, "0Mi": true, "0": true, "0.0Gi": true, "100m": false, "0.5": false, "1Gi": false, "": false} {
+		if got := isZeroQuantity(quantity); got != want {
+			t.Errorf("isZeroQuantity(%q) = %t, want %t", quantity, got, want)
+		}
+	}
+}
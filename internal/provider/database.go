@@ -0,0 +1,320 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &densifyDataSourceDatabase{}
+	_ datasource.DataSourceWithConfigure = &densifyDataSourceDatabase{}
+)
+
+// NewDensifyDataSourceDatabase is a helper function to simplify the provider implementation.
+func NewDensifyDataSourceDatabase() datasource.DataSource {
+	return &densifyDataSourceDatabase{}
+}
+
+// densifyDataSourceDatabase is the data source implementation.
+type densifyDataSourceDatabase struct {
+	provider *densifyProviderData
+}
+
+// densifyDataSourceDatabaseModel maps managed database Recommendation schema data.
+type densifyDataSourceDatabaseModel struct {
+	// lookup arguments
+	AccountNumber types.String `tfsdk:"account_number"`
+	AccountName   types.String `tfsdk:"account_name"`
+	Platform      types.String `tfsdk:"platform"`
+	Name          types.String `tfsdk:"name"`
+
+	EntityId         types.String  `tfsdk:"entity_id"`
+	ResourceId       types.String  `tfsdk:"resource_id"`
+	ServiceType      types.String  `tfsdk:"service_type"`
+	Engine           types.String  `tfsdk:"engine"`
+	OptimizationType types.String  `tfsdk:"optimization_type"`
+	ApprovalType     types.String  `tfsdk:"approval_type"`
+	SavingsEstimate  types.Float64 `tfsdk:"savings_estimate"`
+	EffortEstimate   types.String  `tfsdk:"effort_estimate"`
+
+	CurrentInstanceClass     types.String `tfsdk:"current_instance_class"`
+	RecommendedInstanceClass types.String `tfsdk:"recommended_instance_class"`
+	ApprovedInstanceClass    types.String `tfsdk:"approved_instance_class"`
+	CurrentStorageType       types.String `tfsdk:"current_storage_type"`
+	RecommendedStorageType   types.String `tfsdk:"recommended_storage_type"`
+	CurrentIops              types.Int64  `tfsdk:"current_iops"`
+	RecommendedIops          types.Int64  `tfsdk:"recommended_iops"`
+	CurrentStorageGB         types.Int64  `tfsdk:"current_allocated_storage_gb"`
+	RecommendedStorageGB     types.Int64  `tfsdk:"recommended_allocated_storage_gb"`
+}
+
+// databaseServiceTypes lists the Densify service types of managed databases for each platform.
+var databaseServiceTypes = map[string][]string{
+	"aws":   {"rds", "aurora"},
+	"azure": {"sql database", "azure sql", "sql managed instance"},
+	"gcp":   {"cloud sql"},
+}
+
+// Metadata returns the data source type name.
+func (d *densifyDataSourceDatabase) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_database"
+}
+
+// Schema defines the schema for the data source.
+func (d *densifyDataSourceDatabase) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches a Recommendation for a managed database instance (AWS RDS, Azure SQL, GCP Cloud SQL) from the Densify API.",
+		Attributes: map[string]schema.Attribute{
+			"account_number": accountNumberSchemaAttribute(),
+			"account_name":   accountNameSchemaAttribute(),
+			"platform": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Cloud platform of the database. Defaults to the provider tech_platform. Accepted values are: aws, azure, gcp.",
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Database instance identifier. Ex. the aws_db_instance identifier.",
+			},
+
+			"entity_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for cloud resource.",
+			},
+			"resource_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Cloud resource ID (or ARN) of the database instance.",
+			},
+			"service_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Densify service type of the database. Ex. RDS, SQL Database, Cloud SQL.",
+			},
+			"engine": schema.StringAttribute{
+				Computed:    true,
+				Description: "Database engine. Ex. postgres, mysql, sqlserver.",
+			},
+			"optimization_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Type of optimization. Ex. Downsize, Upsize, Terminate, etc.",
+			},
+			"approval_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Approval type. If ITSM integration has been enabled, this field will identify whether the recommendation has been reviewed & approved.",
+			},
+			"savings_estimate": schema.Float64Attribute{
+				Computed:    true,
+				Description: "Estimated monthly savings by applying the optimization recommendation.",
+			},
+			"effort_estimate": schema.StringAttribute{
+				Computed:    true,
+				Description: "Estimated effort required by applying optimization recommendation. Ex. none, low, med, high.",
+			},
+			"current_instance_class": schema.StringAttribute{
+				Computed:    true,
+				Description: "Current database instance class (or tier). Ex. db.r5.large.",
+			},
+			"recommended_instance_class": schema.StringAttribute{
+				Computed:    true,
+				Description: "Recommended database instance class (or tier) generated by Densify. Use it for the aws_db_instance instance_class.",
+			},
+			"approved_instance_class": schema.StringAttribute{
+				Computed:    true,
+				Description: "The approved database instance class. This starts with the fallback instance or the current instance class, and may only be replaced by the recommended instance class if 'Approval_Type' is set.",
+			},
+			"current_storage_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Current storage type. Ex. gp2, io1.",
+			},
+			"recommended_storage_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Recommended storage type generated by Densify.",
+			},
+			"current_iops": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Current provisioned IOPS.",
+			},
+			"recommended_iops": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Recommended provisioned IOPS.",
+			},
+			"current_allocated_storage_gb": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Current allocated storage (in gigabytes or GB).",
+			},
+			"recommended_allocated_storage_gb": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Recommended allocated storage (in gigabytes or GB).",
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *densifyDataSourceDatabase) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	tflog.Trace(ctx, "Configuring Densify API client")
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*densifyProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *densifyProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = providerData
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *densifyDataSourceDatabase) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Trace(ctx, "Reading Densify API client")
+	ctx, span := startSpan(ctx, "densify_database.Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+	var state densifyDataSourceDatabaseModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	source, ok := d.provider.readSource(ctx, req, resp, cloudAttributes)
+	if !ok {
+		return
+	}
+
+	platform, err := cloudPlatform(&d.provider.settings, state.Platform)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("platform"),
+			"Invalid Database Platform",
+			err.Error(),
+		)
+		return
+	}
+	state.Platform = types.StringValue(platform)
+
+	client, err := source.WithQuery(func(query *densify.DensifyAPIQuery) {
+		setAccount(query, state.AccountNumber, state.AccountName)
+		query.AnalysisTechnology = platform
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create Densify query",
+			"Densify Client Query Error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Densify API client: calling GetAccountOrCluster")
+	client, _, err = d.provider.accounts.resolve(ctx, client)
+	if err != nil {
+		if client.Query().SkipErrors {
+			// skip the error message
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Account Number/Name",
+			err.Error()+d.provider.settings.describeAccount(state.AccountNumber, state.AccountName),
+		)
+		return
+	}
+	tflog.Trace(ctx, "Densify API client: GetAccountOrCluster: success")
+	tflog.Debug(ctx, "Densify API client: calling GetDensifyRecommendations")
+	recos, err := client.Recommendations()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Recommendation",
+			err.Error(),
+		)
+		return
+	}
+	tflog.Trace(ctx, "Densify API client: GetDensifyRecommendations: success")
+
+	name := state.Name.ValueString()
+	matches := filterRecommendations(recos, func(reco *densify.DensifyRecommendation) bool {
+		return isDatabase(reco, platform, name)
+	})
+	tflog.Debug(ctx, fmt.Sprintf(`Num of matching database recommendations: %d`, len(matches)))
+	if len(matches) > 1 {
+		resp.Diagnostics.AddError(
+			"Ambiguous Densify Recommendation",
+			fmt.Sprintf("Found %d database recommendations named %q. Set account_number to select a single account.", len(matches), name),
+		)
+		return
+	}
+	if len(matches) == 0 {
+		if client.Query().SkipErrors {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Recommendation",
+			fmt.Sprintf("No %s database recommendation matches name %q.", platform, name),
+		)
+		return
+	}
+	reco := matches[0]
+
+	// Map response body to model
+	state.setRecommendation(&reco, client.ApprovedType(&reco))
+
+	recordResult(ctx, sourceResult(client))
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// setRecommendation maps a database recommendation, and the instance class approved for it, to the model.
+func (state *densifyDataSourceDatabaseModel) setRecommendation(reco *densify.DensifyRecommendation, approvedType string) {
+	state.EntityId = types.StringValue(reco.EntityId)
+	state.ResourceId = types.StringValue(reco.ResourceId)
+	state.ServiceType = types.StringValue(reco.ServiceType)
+	state.Engine = types.StringValue(reco.Engine)
+	state.OptimizationType = types.StringValue(reco.RecommendationType)
+	state.ApprovalType = types.StringValue(reco.ApprovalType)
+	state.SavingsEstimate = types.Float64Value(float64(reco.SavingsEstimate))
+	state.EffortEstimate = types.StringValue(reco.EffortEstimate)
+
+	state.CurrentInstanceClass = types.StringValue(reco.CurrentType)
+	state.RecommendedInstanceClass = types.StringValue(reco.RecommendedType)
+	state.ApprovedInstanceClass = types.StringValue(approvedType)
+	state.CurrentStorageType = types.StringValue(reco.CurrentStorageType)
+	state.RecommendedStorageType = types.StringValue(reco.RecommendedStorageType)
+	state.CurrentIops = types.Int64Value(int64(reco.CurrentIops))
+	state.RecommendedIops = types.Int64Value(int64(reco.RecommendedIops))
+	state.CurrentStorageGB = types.Int64Value(int64(reco.CurrentStorageSize))
+	state.RecommendedStorageGB = types.Int64Value(int64(reco.RecommendedStorageSize))
+}
+
+// isDatabase reports whether a recommendation of the platform analysis is the managed database with the given name.
+func isDatabase(reco *densify.DensifyRecommendation, platform string, name string) bool {
+	return isServiceType(reco, databaseServiceTypes[platform]) && reco.Name == name
+}
+
+// isServiceType reports whether a recommendation has one of the given Densify service types.
+func isServiceType(reco *densify.DensifyRecommendation, serviceTypes []string) bool {
+	serviceType := strings.ToLower(reco.ServiceType)
+	for _, t := range serviceTypes {
+		if serviceType == t {
+			return true
+		}
+	}
+	return false
+}
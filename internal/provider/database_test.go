@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+func TestDatabaseRecommendation(t *testing.T) {
+	path := writeTestFile(t, exportFormatJSON, []fileRecommendation{
+		// an EC2 instance with the same name as the database.
+		{TechPlatform: "aws", DensifyRecommendation: densify.DensifyRecommendation{Name: "orders", AccountIdRef: "123", ServiceType: "EC2", CurrentType: "m5.large"}},
+		{TechPlatform: "aws", DensifyRecommendation: densify.DensifyRecommendation{
+			EntityId:               "e-db",
+			Name:                   "orders",
+			AccountIdRef:           "123",
+			ServiceType:            "RDS",
+			Engine:                 "postgres",
+			CurrentType:            "db.r5.xlarge",
+			RecommendedType:        "db.r5.large",
+			CurrentStorageType:     "io1",
+			RecommendedStorageType: "gp3",
+			CurrentIops:            3000,
+			RecommendedIops:        1000,
+			CurrentStorageSize:     100,
+			RecommendedStorageSize: 100,
+		}},
+	})
+	source, err := newFileSource(path, &densify.DensifyAPIQuery{AnalysisTechnology: "aws", AccountNumber: "123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.LookupAccount(); err != nil {
+		t.Fatal(err)
+	}
+	recos, err := source.Recommendations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches := filterRecommendations(recos, func(reco *densify.DensifyRecommendation) bool {
+		return isDatabase(reco, "aws", "orders")
+	})
+	if len(matches) != 1 || matches[0].EntityId != "e-db" {
+		t.Fatalf("database recommendations = %v, want the RDS recommendation only", matches)
+	}
+
+	var state densifyDataSourceDatabaseModel
+	state.setRecommendation(&matches[0], source.ApprovedType(&matches[0]))
+	tests := []struct {
+		name string
+		got  attr.Value
+		want attr.Value
+	}{
+		{"service_type", state.ServiceType, types.StringValue("RDS")},
+		{"engine", state.Engine, types.StringValue("postgres")},
+		{"current_instance_class", state.CurrentInstanceClass, types.StringValue("db.r5.xlarge")},
+		{"recommended_instance_class", state.RecommendedInstanceClass, types.StringValue("db.r5.large")},
+		{"approved_instance_class", state.ApprovedInstanceClass, types.StringValue("db.r5.xlarge")},
+		{"current_storage_type", state.CurrentStorageType, types.StringValue("io1")},
+		{"recommended_storage_type", state.RecommendedStorageType, types.StringValue("gp3")},
+		{"current_iops", state.CurrentIops, types.Int64Value(3000)},
+		{"recommended_iops", state.RecommendedIops, types.Int64Value(1000)},
+		{"current_allocated_storage_gb", state.CurrentStorageGB, types.Int64Value(100)},
+		{"recommended_allocated_storage_gb", state.RecommendedStorageGB, types.Int64Value(100)},
+	}
+	for _, test := range tests {
+		if !test.got.Equal(test.want) {
+			t.Errorf("%s = %s, want %s", test.name, test.got, test.want)
+		}
+	}
+}
+
+func TestIsDatabase(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform string
+		reco     densify.DensifyRecommendation
+		want     bool
+	}{
+		{"RDS", "aws", densify.DensifyRecommendation{Name: "orders", ServiceType: "RDS"}, true},
+		{"Aurora", "aws", densify.DensifyRecommendation{Name: "orders", ServiceType: "Aurora"}, true},
+		{"EC2 with the same name", "aws", densify.DensifyRecommendation{Name: "orders", ServiceType: "EC2"}, false},
+		{"other name", "aws", densify.DensifyRecommendation{Name: "orders-replica", ServiceType: "RDS"}, false},
+		{"Azure SQL", "azure", densify.DensifyRecommendation{Name: "orders", ServiceType: "SQL Database"}, true},
+		{"Azure VM", "azure", densify.DensifyRecommendation{Name: "orders", ServiceType: "Virtual Machine"}, false},
+		{"Cloud SQL", "gcp", densify.DensifyRecommendation{Name: "orders", ServiceType: "Cloud SQL"}, true},
+		{"RDS on another platform", "gcp", densify.DensifyRecommendation{Name: "orders", ServiceType: "RDS"}, false},
+		{"no service type", "aws", densify.DensifyRecommendation{Name: "orders"}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isDatabase(&test.reco, test.platform, "orders"); got != test.want {
+				t.Errorf("isDatabase(%q, %q) = %t, want %t", test.reco.ServiceType, test.platform, got, test.want)
+			}
+		})
+	}
+}
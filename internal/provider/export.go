@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+// Export formats of the export command.
+const (
+	exportFormatJSON = "json"
+	exportFormatCSV  = "csv"
+)
+
+// Export runs the export command: it dumps the recommendations of a platform and account (or Kubernetes cluster)
+// to stdout or a file, in the JSON or CSV format read by the file source. The connection settings are read from
+// the same DENSIFY_* environment variables as the provider.
+func Export(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	platform := flags.String("platform", os.Getenv("DENSIFY_TECH_PLATFORM"), "technology platform: aws, azure, gcp, k8s or kubernetes (default DENSIFY_TECH_PLATFORM)")
+	account := flags.String("account", os.Getenv("DENSIFY_ACCOUNT_NUMBER"), "account number of a cloud platform (default DENSIFY_ACCOUNT_NUMBER)")
+	accountName := flags.String("account-name", os.Getenv("DENSIFY_ACCOUNT_NAME"), "account name of a cloud platform, instead of -account. It is written to the file, so that account_name lookups match (default DENSIFY_ACCOUNT_NAME)")
+	cluster := flags.String("cluster", os.Getenv("DENSIFY_CLUSTER"), "Kubernetes cluster (default DENSIFY_CLUSTER)")
+	namespace := flags.String("namespace", os.Getenv("DENSIFY_NAMESPACE"), "Kubernetes namespace; all the namespaces of the cluster when empty (default DENSIFY_NAMESPACE)")
+	format := flags.String("format", exportFormatJSON, "output format: json or csv")
+	output := flags.String("output", "", "file to write the recommendations to (default stdout)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// Load the settings like the provider does, with the flags in place of the provider configuration.
+	densifysettings := DensifySettings{}
+	densifysettings.LoadEnvironmentVariablesSettings(densifyProviderModel{})
+	resp := provider.ConfigureResponse{}
+	densifysettings.ValidateSettings(&resp)
+	if resp.Diagnostics.HasError() {
+		errs := []error{}
+		for _, d := range resp.Diagnostics.Errors() {
+			errs = append(errs, fmt.Errorf("%s: %s", d.Summary(), d.Detail()))
+		}
+		return errors.Join(errs...)
+	}
+
+	*format = strings.ToLower(*format)
+	if *format != exportFormatJSON && *format != exportFormatCSV {
+		return fmt.Errorf("unknown format %q. Accepted values are: json, csv", *format)
+	}
+	*platform = strings.ToLower(*platform)
+	if *platform == "k8s" {
+		*platform = k8sPlatform
+	}
+	if *platform == "" {
+		return fmt.Errorf("no platform is set. Set -platform or the DENSIFY_TECH_PLATFORM environment variable")
+	}
+
+	source, err := densifysettings.newSource(ctx)
+	if err != nil {
+		return err
+	}
+	source, err = source.WithQuery(func(query *densify.DensifyAPIQuery) {
+		query.AnalysisTechnology = *platform
+		query.AccountNumber = *account
+		query.AccountName = *accountName
+		query.K8sCluster = *cluster
+		query.K8sNamespace = *namespace
+		query.SystemName = ""
+		query.K8sControllerType = ""
+		query.K8sPodName = ""
+		query.K8sContainerName = ""
+		query.SkipErrors = false
+		// the recommendations file applies the fallback_instance_type of the environment that reads it.
+		query.FallbackInstance = ""
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create Densify query: %w", err)
+	}
+	if _, err := source.LookupAccount(); err != nil {
+		return fmt.Errorf("unable to find the Densify account or cluster: %w", err)
+	}
+	recos, err := source.Recommendations()
+	if err != nil {
+		return fmt.Errorf("unable to get the Densify recommendations: %w", err)
+	}
+
+	exported := make([]fileRecommendation, 0, len(recos))
+	for i := range recos {
+		recos[i].ApprovedType = exportedApprovedType(&recos[i])
+		exported = append(exported, fileRecommendation{TechPlatform: *platform, AccountName: *accountName, DensifyRecommendation: recos[i]})
+	}
+
+	if *output == "" {
+		return writeRecommendations(stdout, *format, exported)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := writeRecommendations(f, *format, exported); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// exportedApprovedType returns the approved type of a recommendation as approved in Densify: the recommended
+// type once approved, otherwise empty. The client fills in the current type of unapproved recommendations, which
+// the file source replaces with the fallback or current type when the file is read.
+func exportedApprovedType(reco *densify.DensifyRecommendation) string {
+	if reco.RecommendedType != "" && reco.ApprovedType == reco.RecommendedType {
+		return reco.RecommendedType
+	}
+	return ""
+}
+
+// writeRecommendations writes exported recommendations in the JSON or CSV format.
+func writeRecommendations(w io.Writer, format string, recos []fileRecommendation) error {
+	if format == exportFormatCSV {
+		return writeRecommendationsCSV(w, recos)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(recos)
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+func TestExportedApprovedType(t *testing.T) {
+	tests := []struct {
+		name string
+		reco densify.DensifyRecommendation
+		want string
+	}{
+		{"approved", densify.DensifyRecommendation{CurrentType: "m5.xlarge", RecommendedType: "m5.large", ApprovedType: "m5.large"}, "m5.large"},
+		{"not approved", densify.DensifyRecommendation{CurrentType: "m5.xlarge", RecommendedType: "m5.large", ApprovedType: "m5.xlarge"}, ""},
+		{"fallback", densify.DensifyRecommendation{CurrentType: "m5.xlarge", RecommendedType: "m5.large", ApprovedType: "t3.large"}, ""},
+		{"no recommendation", densify.DensifyRecommendation{CurrentType: "m5.xlarge"}, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := exportedApprovedType(&test.reco); got != test.want {
+				t.Errorf("exportedApprovedType() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestExportedApprovalsReadByFileSource(t *testing.T) {
+	exported := []fileRecommendation{
+		{TechPlatform: "aws", DensifyRecommendation: densify.DensifyRecommendation{Name: "approved", AccountIdRef: "123", CurrentType: "m5.xlarge", RecommendedType: "m5.large", ApprovedType: "m5.large"}},
+		{TechPlatform: "aws", DensifyRecommendation: densify.DensifyRecommendation{Name: "pending", AccountIdRef: "123", CurrentType: "m5.xlarge", RecommendedType: "m5.large", ApprovedType: "m5.xlarge"}},
+	}
+	for i := range exported {
+		exported[i].ApprovedType = exportedApprovedType(&exported[i].DensifyRecommendation)
+	}
+	for _, format := range []string{exportFormatJSON, exportFormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeRecommendations(&buf, format, exported); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), "recos."+format)
+			if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			source, err := newFileSource(path, &densify.DensifyAPIQuery{AnalysisTechnology: "aws", AccountNumber: "123", FallbackInstance: "t3.large"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]string{"approved": "m5.large", "pending": "t3.large"}
+			for i := range source.recos {
+				reco := &source.recos[i].DensifyRecommendation
+				if got := source.ApprovedType(reco); got != want[reco.Name] {
+					t.Errorf("ApprovedType(%s) = %q, want %q", reco.Name, got, want[reco.Name])
+				}
+			}
+		})
+	}
+}
+
+func TestExportAccountName(t *testing.T) {
+	path := writeTestFile(t, exportFormatJSON, []fileRecommendation{
+		{TechPlatform: "aws", AccountName: "prod", DensifyRecommendation: densify.DensifyRecommendation{Name: "web-01", AccountIdRef: "123"}},
+		{TechPlatform: "aws", AccountName: "dev", DensifyRecommendation: densify.DensifyRecommendation{Name: "web-02", AccountIdRef: "456"}},
+	})
+	for _, name := range []string{"DENSIFY_TECH_PLATFORM", "DENSIFY_ACCOUNT_NUMBER", "DENSIFY_ACCOUNT_NAME", "DENSIFY_CLUSTER", "DENSIFY_NAMESPACE"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("DENSIFY_SOURCE", sourceTypeFile)
+	t.Setenv("DENSIFY_SOURCE_FILE", path)
+
+	output := filepath.Join(t.TempDir(), "export.json")
+	if err := Export(context.Background(), []string{"-platform", "aws", "-account-name", "prod", "-output", output}, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	source, err := newFileSource(output, &densify.DensifyAPIQuery{AnalysisTechnology: "aws", AccountName: "prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(source.recos) != 1 || source.recos[0].Name != "web-01" || source.recos[0].AccountName != "prod" {
+		t.Fatalf("exported recommendations = %v, want web-01 of account prod", source.recos)
+	}
+	if account, err := source.LookupAccount(); err != nil || account != "123" {
+		t.Errorf("LookupAccount() = %q, %v, want %q", account, err, "123")
+	}
+}
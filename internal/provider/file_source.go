@@ -0,0 +1,388 @@
+package provider
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+// Recommendation sources of the provider.
+const (
+	sourceTypeAPI  = "api"
+	sourceTypeFile = "file"
+)
+
+// sourceTypes lists the accepted values of the provider source.
+var sourceTypes = []string{sourceTypeAPI, sourceTypeFile}
+
+// fileRecommendation is a recommendation of a recommendations file, with the technology platform and account name
+// it was exported for. Recommendations without a platform match every platform.
+type fileRecommendation struct {
+	TechPlatform string `json:"tech_platform"`
+	AccountName  string `json:"account_name,omitempty"`
+	densify.DensifyRecommendation
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ RecommendationSource = &fileSource{}
+
+// fileSource reads the recommendations from a JSON or CSV file exported from Densify, for environments that
+// cannot reach the Densify API. The lookups follow the same rules as the API.
+type fileSource struct {
+	path  string
+	recos []fileRecommendation
+	query *densify.DensifyAPIQuery
+}
+
+// newFileSource loads a recommendations file. Files with a .csv extension are read as CSV, others as JSON.
+func newFileSource(path string, query *densify.DensifyAPIQuery) (*fileSource, error) {
+	if path == "" {
+		return nil, fmt.Errorf("source_file must be set when source is %q", sourceTypeFile)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var recos []fileRecommendation
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		recos, err = readRecommendationsCSV(f)
+	} else {
+		err = json.NewDecoder(f).Decode(&recos)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the recommendations file %s: %w", path, err)
+	}
+	return &fileSource{path: path, recos: recos, query: query}, nil
+}
+
+// fileSourcePrefix starts the names of file sources.
+const fileSourcePrefix = "file:"
+
+// Name returns the path of the recommendations file.
+func (s *fileSource) Name() string {
+	return fileSourcePrefix + s.path
+}
+
+// Query returns the query of the source.
+func (s *fileSource) Query() *densify.DensifyAPIQuery {
+	return s.query
+}
+
+// WithQuery returns a copy of the source with its query adjusted by configure.
+func (s *fileSource) WithQuery(configure func(query *densify.DensifyAPIQuery)) (RecommendationSource, error) {
+	query := *s.query
+	configure(&query)
+	if query.AnalysisTechnology == "" {
+		return nil, fmt.Errorf("no technology platform is set")
+	}
+	return &fileSource{path: s.path, recos: s.recos, query: &query}, nil
+}
+
+// WithContext returns the source, since reading the file is not logged or traced.
+func (s *fileSource) WithContext(_ context.Context) RecommendationSource {
+	return s
+}
+
+// LookupAccount checks that the file has recommendations for the account (or cluster) of the query.
+func (s *fileSource) LookupAccount() (string, error) {
+	if s.isK8s() {
+		if s.query.K8sCluster == "" {
+			return "", fmt.Errorf("no Kubernetes cluster is set")
+		}
+		for i := range s.recos {
+			if s.matchesPlatform(&s.recos[i]) && s.recos[i].Cluster == s.query.K8sCluster {
+				return s.query.K8sCluster, nil
+			}
+		}
+		return "", fmt.Errorf("cluster %q has no recommendations in %s", s.query.K8sCluster, s.path)
+	}
+
+	if s.query.AccountNumber == "" && s.query.AccountName == "" {
+		return "", fmt.Errorf("no account is selected. Set account_number or account_name on the provider or the data source")
+	}
+	for i := range s.recos {
+		if s.matchesPlatform(&s.recos[i]) && s.matchesAccount(&s.recos[i]) {
+			return s.recos[i].AccountIdRef, nil
+		}
+	}
+	if s.query.AccountNumber == "" {
+		return "", fmt.Errorf("account name %q has no %s recommendations in %s. Export the file with -account-name to look up accounts by name", s.query.AccountName, s.query.AnalysisTechnology, s.path)
+	}
+	return "", fmt.Errorf("account %q has no %s recommendations in %s", s.query.AccountNumber, s.query.AnalysisTechnology, s.path)
+}
+
+// WithResolvedAccount returns the source, since looking up an account in the file keeps no state.
+func (s *fileSource) WithResolvedAccount(_ RecommendationSource) RecommendationSource {
+	return s
+}
+
+// Recommendation returns the recommendation of the query system name, or of the query controller for
+// Kubernetes, or nil if there is none.
+func (s *fileSource) Recommendation() (*densify.DensifyRecommendation, error) {
+	recos, err := s.Recommendations()
+	if err != nil {
+		return nil, err
+	}
+	for i := range recos {
+		reco := &recos[i]
+		if s.isK8s() {
+			if strings.EqualFold(reco.ControllerType, s.query.K8sControllerType) && reco.PodService == s.query.K8sPodName {
+				if s.query.K8sContainerName != "" {
+					reco.Containers = filterContainers(reco.Containers, s.query.K8sContainerName)
+				}
+				return reco, nil
+			}
+		} else if reco.Name == s.query.SystemName {
+			return reco, nil
+		}
+	}
+	return nil, nil
+}
+
+// Recommendations returns the recommendations of the query account, or of the query cluster and namespace
+// for Kubernetes.
+func (s *fileSource) Recommendations() ([]densify.DensifyRecommendation, error) {
+	recos := []densify.DensifyRecommendation{}
+	for i := range s.recos {
+		reco := &s.recos[i]
+		if !s.matchesPlatform(reco) {
+			continue
+		}
+		if s.isK8s() {
+			if reco.Cluster != s.query.K8sCluster || (s.query.K8sNamespace != "" && reco.Namespace != s.query.K8sNamespace) {
+				continue
+			}
+		} else if !s.matchesAccount(reco) {
+			continue
+		}
+		recos = append(recos, s.withFallbacks(reco.DensifyRecommendation))
+	}
+	return recos, nil
+}
+
+// ApprovedType returns the approved type of the export, or the fallback (or current) type when the recommendation
+// was not approved.
+func (s *fileSource) ApprovedType(reco *densify.DensifyRecommendation) string {
+	if reco.ApprovedType != "" {
+		return reco.ApprovedType
+	}
+	if s.query.FallbackInstance != "" {
+		return s.query.FallbackInstance
+	}
+	return reco.CurrentType
+}
+
+// isK8s reports whether the query is for Kubernetes recommendations.
+func (s *fileSource) isK8s() bool {
+	platform := strings.ToLower(s.query.AnalysisTechnology)
+	return platform == k8sPlatform || platform == "k8s"
+}
+
+// matchesPlatform reports whether a recommendation was exported for the platform of the query.
+func (s *fileSource) matchesPlatform(reco *fileRecommendation) bool {
+	if reco.TechPlatform == "" {
+		return true
+	}
+	platform := strings.ToLower(reco.TechPlatform)
+	if platform == "k8s" || platform == k8sPlatform {
+		return s.isK8s()
+	}
+	return platform == strings.ToLower(s.query.AnalysisTechnology)
+}
+
+// matchesAccount reports whether a recommendation belongs to the account of the query: its account number, or
+// its account name when the query has no account number. All the recommendations match when neither is set.
+func (s *fileSource) matchesAccount(reco *fileRecommendation) bool {
+	if s.query.AccountNumber != "" {
+		return reco.AccountIdRef == s.query.AccountNumber
+	}
+	if s.query.AccountName != "" {
+		return reco.AccountName == s.query.AccountName
+	}
+	return true
+}
+
+// withFallbacks returns a copy of a recommendation whose containers carry the fallback values of the query, as
+// the Densify client does for the API recommendations.
+func (s *fileSource) withFallbacks(reco densify.DensifyRecommendation) densify.DensifyRecommendation {
+	if len(reco.Containers) == 0 {
+		return reco
+	}
+	containers := make([]densify.DensifyContainerRecommendation, len(reco.Containers))
+	copy(containers, reco.Containers)
+	for i := range containers {
+		c := &containers[i]
+		c.FallbackCpuRequest = fallbackString(c.FallbackCpuRequest, s.query.FallbackCPURequest)
+		c.FallbackCpuLimit = fallbackString(c.FallbackCpuLimit, s.query.FallbackCPULimit)
+		c.FallbackMemRequest = fallbackString(c.FallbackMemRequest, s.query.FallbackMemRequest)
+		c.FallbackMemLimit = fallbackString(c.FallbackMemLimit, s.query.FallbackMemLimit)
+	}
+	reco.Containers = containers
+	return reco
+}
+
+// fallbackString returns value, or fallback when value is empty.
+func fallbackString(value string, fallback string) string {
+	if value != "" {
+		return value
+	}
+	return fallback
+}
+
+// filterContainers returns the container recommendations of the named container.
+func filterContainers(containers []densify.DensifyContainerRecommendation, name string) []densify.DensifyContainerRecommendation {
+	matches := []densify.DensifyContainerRecommendation{}
+	for _, c := range containers {
+		if c.Container == name {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+// recommendationColumns returns the CSV column of each field of a file recommendation: its JSON name, so that
+// the CSV and JSON files share the same names. Nested values (ex. containers or tags) are JSON encoded cells.
+func recommendationColumns() ([]string, [][]int) {
+	names := []string{}
+	indexes := [][]int{}
+	for _, f := range reflect.VisibleFields(reflect.TypeOf(fileRecommendation{})) {
+		if f.Anonymous || !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			tag, _, _ = strings.Cut(tag, ",")
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		names = append(names, name)
+		indexes = append(indexes, f.Index)
+	}
+	return names, indexes
+}
+
+// readRecommendationsCSV reads the recommendations of a CSV file with a header row. Unknown columns are ignored.
+func readRecommendationsCSV(r io.Reader) ([]fileRecommendation, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return []fileRecommendation{}, nil
+	}
+
+	names, indexes := recommendationColumns()
+	fields := map[string][]int{}
+	for i, name := range names {
+		fields[strings.ToLower(name)] = indexes[i]
+	}
+	columns := make([][]int, len(rows[0]))
+	for i, name := range rows[0] {
+		columns[i] = fields[strings.ToLower(strings.TrimSpace(name))]
+	}
+
+	recos := make([]fileRecommendation, 0, len(rows)-1)
+	for line, row := range rows[1:] {
+		var reco fileRecommendation
+		v := reflect.ValueOf(&reco).Elem()
+		for i, cell := range row {
+			if columns[i] == nil || cell == "" {
+				continue
+			}
+			if err := setCSVValue(v.FieldByIndex(columns[i]), cell); err != nil {
+				return nil, fmt.Errorf("line %d, column %s: %w", line+2, rows[0][i], err)
+			}
+		}
+		recos = append(recos, reco)
+	}
+	return recos, nil
+}
+
+// setCSVValue parses a CSV cell into a recommendation field.
+func setCSVValue(field reflect.Value, cell string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(cell)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(cell, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	default:
+		return json.Unmarshal([]byte(cell), field.Addr().Interface())
+	}
+	return nil
+}
+
+// writeRecommendationsCSV writes recommendations as a CSV file with a header row, in the format read by
+// readRecommendationsCSV.
+func writeRecommendationsCSV(w io.Writer, recos []fileRecommendation) error {
+	names, indexes := recommendationColumns()
+	writer := csv.NewWriter(w)
+	if err := writer.Write(names); err != nil {
+		return err
+	}
+	for i := range recos {
+		v := reflect.ValueOf(&recos[i]).Elem()
+		row := make([]string, len(indexes))
+		for j, index := range indexes {
+			cell, err := csvValue(v.FieldByIndex(index))
+			if err != nil {
+				return err
+			}
+			row[j] = cell
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvValue formats a recommendation field as a CSV cell. Empty nested values are written as empty cells.
+func csvValue(field reflect.Value) (string, error) {
+	switch field.Kind() {
+	case reflect.String:
+		return field.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(field.Int(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(field.Float(), 'f', -1, field.Type().Bits()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool()), nil
+	}
+	if field.IsZero() || ((field.Kind() == reflect.Slice || field.Kind() == reflect.Map) && field.Len() == 0) {
+		return "", nil
+	}
+	b, err := json.Marshal(field.Interface())
+	return string(b), err
+}
@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+// testFileRecommendations has a recommendation with every kind of field set, and one with only a few.
+var testFileRecommendations = []fileRecommendation{
+	{
+		TechPlatform: "aws",
+		AccountName:  "prod",
+		DensifyRecommendation: densify.DensifyRecommendation{
+			EntityId:           "e-1",
+			Name:               "web-01",
+			CurrentType:        "m5.xlarge",
+			RecommendedType:    "m5.large",
+			ApprovedType:       "m5.large",
+			RecommendationType: "Downsize",
+			AccountIdRef:       "123456789012",
+			SavingsEstimate:    42.5,
+			EffortEstimate:     "Low",
+			CurrentCpu:         4,
+			CurrentMemory:      15.25,
+			RecommLastSeen:     1717200000000,
+			ResourceId:         "arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0",
+			Tags:               map[string]string{"team": "shop", "env": "prod, eu"},
+			Disks: []densify.DensifyDiskRecommendation{
+				{Name: "/dev/xvda", CurrentType: "gp2", RecommendedType: "gp3", CurrentSize: 100, RecommendedSize: 100},
+			},
+		},
+	},
+	{
+		TechPlatform: "kubernetes",
+		DensifyRecommendation: densify.DensifyRecommendation{
+			Cluster:        "prod",
+			Namespace:      "shop",
+			ControllerType: "Deployment",
+			PodService:     "web",
+			Labels:         map[string]string{"app": "web"},
+			Containers: []densify.DensifyContainerRecommendation{
+				{
+					Container:             "app",
+					CurrentCpuRequest:     500,
+					RecommendedCpuRequest: 250,
+					ExtendedResources:     []densify.DensifyExtendedResource{{Name: "nvidia.com/gpu", CurrentRequest: 1, RecommendedRequest: 1}},
+				},
+				{Container: "migrate", ContainerType: "init"},
+			},
+		},
+	},
+	{
+		DensifyRecommendation: densify.DensifyRecommendation{Name: "db-01", AccountIdRef: "210987654321", CurrentType: "t3.large"},
+	},
+}
+
+// writeTestFile writes recommendations to a recommendations file of the given format.
+func writeTestFile(t *testing.T, format string, recos []fileRecommendation) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := writeRecommendations(&buf, format, recos); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "recos."+format)
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRecommendationsFileRoundTrip(t *testing.T) {
+	for _, format := range []string{exportFormatJSON, exportFormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			path := writeTestFile(t, format, testFileRecommendations)
+			source, err := newFileSource(path, &densify.DensifyAPIQuery{AnalysisTechnology: "aws"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(source.recos, testFileRecommendations) {
+				got, _ := json.Marshal(source.recos)
+				want, _ := json.Marshal(testFileRecommendations)
+				t.Errorf("read recommendations = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestReadRecommendationsCSVColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recos.csv")
+	content := "Name, currentType ,Unknown,SavingsEstimate\nweb-01,m5.xlarge,x,12.5\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, err := newFileSource(path, &densify.DensifyAPIQuery{AnalysisTechnology: "aws"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []fileRecommendation{{DensifyRecommendation: densify.DensifyRecommendation{Name: "web-01", CurrentType: "m5.xlarge", SavingsEstimate: 12.5}}}
+	if !reflect.DeepEqual(source.recos, want) {
+		t.Errorf("read recommendations = %+v, want %+v", source.recos, want)
+	}
+
+	if err := os.WriteFile(path, []byte("Name,CurrentCpu\nweb-01,four\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newFileSource(path, &densify.DensifyAPIQuery{AnalysisTechnology: "aws"}); err == nil {
+		t.Error("newFileSource() error = nil, want an invalid CurrentCpu error")
+	}
+}
+
+func TestFileSourceLookupAccount(t *testing.T) {
+	path := writeTestFile(t, exportFormatCSV, testFileRecommendations)
+	tests := []struct {
+		name    string
+		query   densify.DensifyAPIQuery
+		want    string
+		wantErr bool
+	}{
+		{"account", densify.DensifyAPIQuery{AnalysisTechnology: "aws", AccountNumber: "123456789012"}, "123456789012", false},
+		{"account without platform", densify.DensifyAPIQuery{AnalysisTechnology: "azure", AccountNumber: "210987654321"}, "210987654321", false},
+		{"account of another platform", densify.DensifyAPIQuery{AnalysisTechnology: "azure", AccountNumber: "123456789012"}, "", true},
+		{"unknown account", densify.DensifyAPIQuery{AnalysisTechnology: "aws", AccountNumber: "999"}, "", true},
+		{"account name", densify.DensifyAPIQuery{AnalysisTechnology: "aws", AccountName: "prod"}, "123456789012", false},
+		{"account name of another platform", densify.DensifyAPIQuery{AnalysisTechnology: "azure", AccountName: "prod"}, "", true},
+		{"unknown account name", densify.DensifyAPIQuery{AnalysisTechnology: "aws", AccountName: "dev"}, "", true},
+		{"no account", densify.DensifyAPIQuery{AnalysisTechnology: "aws"}, "", true},
+		{"cluster", densify.DensifyAPIQuery{AnalysisTechnology: "kubernetes", K8sCluster: "prod"}, "prod", false},
+		{"unknown cluster", densify.DensifyAPIQuery{AnalysisTechnology: "k8s", K8sCluster: "dev"}, "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := test.query
+			source, err := newFileSource(path, &query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := source.LookupAccount()
+			if (err != nil) != test.wantErr || got != test.want {
+				t.Errorf("LookupAccount() = %q, %v, want %q, error %t", got, err, test.want, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			recos, err := source.Recommendations()
+			if err != nil || len(recos) != 1 {
+				t.Errorf("Recommendations() = %v, %v, want the recommendation of the account", recos, err)
+			}
+		})
+	}
+}
+
+func TestSourceResult(t *testing.T) {
+	path := writeTestFile(t, exportFormatJSON, []fileRecommendation{})
+	file, err := newFileSource(path, &densify.DensifyAPIQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := &apiSource{client: &densify.DensifyClient{BaseURL: "https://densify"}, username: "reader"}
+	if got := sourceResult(file); got != resultFile {
+		t.Errorf("sourceResult(file) = %q, want %q", got, resultFile)
+	}
+	if got := sourceResult(api); got != resultAPI {
+		t.Errorf("sourceResult(api) = %q, want %q", got, resultAPI)
+	}
+}
@@ -0,0 +1,330 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &densifyDataSourceGCPInstance{}
+	_ datasource.DataSourceWithConfigure = &densifyDataSourceGCPInstance{}
+)
+
+// NewDensifyDataSourceGCPInstance is a helper function to simplify the provider implementation.
+func NewDensifyDataSourceGCPInstance() datasource.DataSource {
+	return &densifyDataSourceGCPInstance{}
+}
+
+// densifyDataSourceGCPInstance is the data source implementation.
+type densifyDataSourceGCPInstance struct {
+	provider *densifyProviderData
+}
+
+// densifyDataSourceGCPInstanceModel maps GCP Compute Engine Recommendation schema data.
+type densifyDataSourceGCPInstanceModel struct {
+	// lookup arguments
+	AccountNumber types.String `tfsdk:"account_number"`
+	AccountName   types.String `tfsdk:"account_name"`
+	Project       types.String `tfsdk:"project"`
+	Zone          types.String `tfsdk:"zone"`
+	InstanceName  types.String `tfsdk:"instance_name"`
+
+	EntityId         types.String  `tfsdk:"entity_id"`
+	ResourceId       types.String  `tfsdk:"resource_id"`
+	OptimizationType types.String  `tfsdk:"optimization_type"`
+	ApprovalType     types.String  `tfsdk:"approval_type"`
+	SavingsEstimate  types.Float64 `tfsdk:"savings_estimate"`
+	EffortEstimate   types.String  `tfsdk:"effort_estimate"`
+
+	CurrentMachineType     types.String `tfsdk:"current_machine_type"`
+	CurrentVCPUs           types.Int64  `tfsdk:"current_vcpus"`
+	CurrentMemoryMB        types.Int64  `tfsdk:"current_memory_mb"`
+	CurrentIsCustom        types.Bool   `tfsdk:"current_is_custom"`
+	RecommendedMachineType types.String `tfsdk:"recommended_machine_type"`
+	RecommendedVCPUs       types.Int64  `tfsdk:"recommended_vcpus"`
+	RecommendedMemoryMB    types.Int64  `tfsdk:"recommended_memory_mb"`
+	RecommendedIsCustom    types.Bool   `tfsdk:"recommended_is_custom"`
+	ApprovedMachineType    types.String `tfsdk:"approved_machine_type"`
+}
+
+// Metadata returns the data source type name.
+func (d *densifyDataSourceGCPInstance) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_gcp_instance"
+}
+
+// Schema defines the schema for the data source.
+func (d *densifyDataSourceGCPInstance) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches a Recommendation for a GCP Compute Engine instance from the Densify API, including custom machine types decomposed into vCPUs and memory.",
+		Attributes: map[string]schema.Attribute{
+			"account_number": accountNumberSchemaAttribute(),
+			"account_name":   accountNameSchemaAttribute(),
+			"project": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "GCP project ID of the instance. Defaults to any project in the Densify account.",
+			},
+			"zone": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "GCP zone of the instance. Ex. us-central1-a.",
+			},
+			"instance_name": schema.StringAttribute{
+				Required:    true,
+				Description: "GCP Compute Engine instance name.",
+			},
+
+			"entity_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for cloud resource.",
+			},
+			"resource_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "GCP resource ID of the instance.",
+			},
+			"optimization_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Type of optimization. Ex. Downsize, Upsize, Terminate, etc.",
+			},
+			"approval_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Approval type. If ITSM integration has been enabled, this field will identify whether the recommendation has been reviewed & approved.",
+			},
+			"savings_estimate": schema.Float64Attribute{
+				Computed:    true,
+				Description: "Estimated monthly savings by applying the optimization recommendation.",
+			},
+			"effort_estimate": schema.StringAttribute{
+				Computed:    true,
+				Description: "Estimated effort required by applying optimization recommendation. Ex. none, low, med, high.",
+			},
+			"current_machine_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Current machine type. Ex. n2-standard-4, custom-4-16384.",
+			},
+			"current_vcpus": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of vCPUs of the current machine type. Shared-core types (ex. e2-custom-medium-4096) have 2 vCPUs.",
+			},
+			"current_memory_mb": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Memory of the current machine type (in megabytes or MB).",
+			},
+			"current_is_custom": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the current machine type is a custom machine type.",
+			},
+			"recommended_machine_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Recommended machine type generated by Densify.",
+			},
+			"recommended_vcpus": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of vCPUs of the recommended machine type. Shared-core types (ex. e2-custom-medium-4096) have 2 vCPUs.",
+			},
+			"recommended_memory_mb": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Memory of the recommended machine type (in megabytes or MB).",
+			},
+			"recommended_is_custom": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the recommended machine type is a custom machine type.",
+			},
+			"approved_machine_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "The approved machine type. This starts with the fallback instance or the current machine type, and may only be replaced by the recommended machine type if 'Approval_Type' is set.",
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *densifyDataSourceGCPInstance) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	tflog.Trace(ctx, "Configuring Densify API client")
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*densifyProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *densifyProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = providerData
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *densifyDataSourceGCPInstance) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Trace(ctx, "Reading Densify API client")
+	ctx, span := startSpan(ctx, "densify_gcp_instance.Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+	var state densifyDataSourceGCPInstanceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	source, ok := d.provider.readSource(ctx, req, resp, cloudAttributes)
+	if !ok {
+		return
+	}
+
+	client, err := source.WithQuery(func(query *densify.DensifyAPIQuery) {
+		setAccount(query, state.AccountNumber, state.AccountName)
+		query.AnalysisTechnology = "gcp"
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create Densify query",
+			"Densify Client Query Error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Densify API client: calling GetAccountOrCluster")
+	client, _, err = d.provider.accounts.resolve(ctx, client)
+	if err != nil {
+		if client.Query().SkipErrors {
+			// skip the error message
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Account Number/Name",
+			err.Error()+d.provider.settings.describeAccount(state.AccountNumber, state.AccountName),
+		)
+		return
+	}
+	tflog.Trace(ctx, "Densify API client: GetAccountOrCluster: success")
+	tflog.Debug(ctx, "Densify API client: calling GetDensifyRecommendations")
+	recos, err := client.Recommendations()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Recommendation",
+			err.Error(),
+		)
+		return
+	}
+	tflog.Trace(ctx, "Densify API client: GetDensifyRecommendations: success")
+
+	matches := filterRecommendations(recos, state.matches)
+	tflog.Debug(ctx, fmt.Sprintf(`Num of matching GCP recommendations: %d`, len(matches)))
+	if len(matches) > 1 {
+		resp.Diagnostics.AddError(
+			"Ambiguous Densify Recommendation",
+			fmt.Sprintf("Found %d GCP recommendations for instance %q. Set project and zone to select a single instance.", len(matches), state.InstanceName.ValueString()),
+		)
+		return
+	}
+	if len(matches) == 0 {
+		if client.Query().SkipErrors {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Recommendation",
+			fmt.Sprintf("No GCP Compute Engine recommendation matches instance %q.", state.InstanceName.ValueString()),
+		)
+		return
+	}
+	reco := matches[0]
+
+	// Map response body to model
+	resourcePath := parseResourcePath(reco.ResourceId)
+	state.Project = types.StringValue(resourcePath.get("projects"))
+	if state.Project.ValueString() == "" {
+		state.Project = types.StringValue(reco.AccountIdRef)
+	}
+	state.Zone = types.StringValue(resourcePath.get("zones"))
+	state.EntityId = types.StringValue(reco.EntityId)
+	state.ResourceId = types.StringValue(reco.ResourceId)
+	state.OptimizationType = types.StringValue(reco.RecommendationType)
+	state.ApprovalType = types.StringValue(reco.ApprovalType)
+	state.SavingsEstimate = types.Float64Value(float64(reco.SavingsEstimate))
+	state.EffortEstimate = types.StringValue(reco.EffortEstimate)
+
+	current := newGCPMachineType(reco.CurrentType, reco.CurrentCpu, reco.CurrentMemory)
+	state.CurrentMachineType = types.StringValue(reco.CurrentType)
+	state.CurrentVCPUs = types.Int64Value(current.vcpus)
+	state.CurrentMemoryMB = types.Int64Value(current.memoryMB)
+	state.CurrentIsCustom = types.BoolValue(current.custom)
+
+	recommended := newGCPMachineType(reco.RecommendedType, reco.RecommendedCpu, reco.RecommendedMemory)
+	state.RecommendedMachineType = types.StringValue(reco.RecommendedType)
+	state.RecommendedVCPUs = types.Int64Value(recommended.vcpus)
+	state.RecommendedMemoryMB = types.Int64Value(recommended.memoryMB)
+	state.RecommendedIsCustom = types.BoolValue(recommended.custom)
+	state.ApprovedMachineType = types.StringValue(client.ApprovedType(&reco))
+
+	recordResult(ctx, sourceResult(client))
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// matches reports whether a recommendation is for the configured instance.
+func (config *densifyDataSourceGCPInstanceModel) matches(reco *densify.DensifyRecommendation) bool {
+	resourcePath := parseResourcePath(reco.ResourceId)
+	name := resourcePath.get("instances")
+	if name == "" {
+		name = reco.Name
+	}
+	if name != config.InstanceName.ValueString() {
+		return false
+	}
+	if !config.Project.IsNull() && resourcePath.get("projects") != config.Project.ValueString() && reco.AccountIdRef != config.Project.ValueString() {
+		return false
+	}
+	if !config.Zone.IsNull() && resourcePath.get("zones") != config.Zone.ValueString() {
+		return false
+	}
+	return true
+}
+
+// gcpCustomMachineType matches custom machine types, ex. custom-4-16384, n2-custom-8-32768, n2d-custom-2-16384-ext,
+// or shared-core E2 custom types such as e2-custom-medium-4096.
+var gcpCustomMachineType = regexp.MustCompile(`^(?:[a-z0-9]+-)?custom-(\d+|micro|small|medium)-(\d+)(?:-ext)?$`)
+
+// gcpSharedCoreVCPUs is the number of vCPUs of shared-core machine types, which get a fraction of their time.
+const gcpSharedCoreVCPUs = 2
+
+// gcpMachineType is a GCP machine type decomposed into vCPUs and memory.
+type gcpMachineType struct {
+	vcpus    int64
+	memoryMB int64
+	custom   bool
+}
+
+// newGCPMachineType decomposes a machine type. Custom machine types carry their vCPUs and memory in the name,
+// predefined machine types use the vCPU and memory (GiB) values from the Densify API.
+func newGCPMachineType(machineType string, cpu int, memoryGiB float32) gcpMachineType {
+	if m := gcpCustomMachineType.FindStringSubmatch(strings.ToLower(machineType)); m != nil {
+		vcpus, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			// micro, small or medium shared core
+			vcpus = gcpSharedCoreVCPUs
+		}
+		memoryMB, _ := strconv.ParseInt(m[2], 10, 64)
+		return gcpMachineType{vcpus: vcpus, memoryMB: memoryMB, custom: true}
+	}
+	return gcpMachineType{vcpus: int64(cpu), memoryMB: int64(memoryGiB * 1024)}
+}
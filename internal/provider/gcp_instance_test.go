@@ -0,0 +1,32 @@
+package provider
+
+import "testing"
+
+func TestNewGCPMachineType(t *testing.T) {
+	tests := []struct {
+		machineType string
+		cpu         int
+		memoryGiB   float32
+		want        gcpMachineType
+	}{
+		{"custom-4-16384", 0, 0, gcpMachineType{vcpus: 4, memoryMB: 16384, custom: true}},
+		{"n2-custom-8-32768", 0, 0, gcpMachineType{vcpus: 8, memoryMB: 32768, custom: true}},
+		{"n2d-custom-2-16384-ext", 0, 0, gcpMachineType{vcpus: 2, memoryMB: 16384, custom: true}},
+		{"N2-Custom-8-32768", 0, 0, gcpMachineType{vcpus: 8, memoryMB: 32768, custom: true}},
+		{"e2-custom-medium-4096", 0, 0, gcpMachineType{vcpus: 2, memoryMB: 4096, custom: true}},
+		{"e2-custom-small-2048", 0, 0, gcpMachineType{vcpus: 2, memoryMB: 2048, custom: true}},
+		{"e2-custom-micro-1024", 0, 0, gcpMachineType{vcpus: 2, memoryMB: 1024, custom: true}},
+		{"e2-custom-large-4096", 2, 4, gcpMachineType{vcpus: 2, memoryMB: 4096}},
+		{"e2-standard-4", 4, 16, gcpMachineType{vcpus: 4, memoryMB: 16384}},
+		{"e2-medium", 2, 4, gcpMachineType{vcpus: 2, memoryMB: 4096}},
+		{"f1-micro", 1, 0.6, gcpMachineType{vcpus: 1, memoryMB: 614}},
+		{"custom-4", 4, 8, gcpMachineType{vcpus: 4, memoryMB: 8192}},
+	}
+	for _, test := range tests {
+		t.Run(test.machineType, func(t *testing.T) {
+			if got := newGCPMachineType(test.machineType, test.cpu, test.memoryGiB); got != test.want {
+				t.Errorf("newGCPMachineType(%q, %d, %g) = %+v, want %+v", test.machineType, test.cpu, test.memoryGiB, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+// hpaThresholdTolerance is the relative change of the effective HPA CPU threshold that triggers a warning.
+const hpaThresholdTolerance = 0.2
+
+// densifyDataSourceHPAModel maps the HorizontalPodAutoscaler recommendation of a container data source.
+type densifyDataSourceHPAModel struct {
+	CurrentMinReplicas              types.Int64 `tfsdk:"current_min_replicas"`
+	CurrentMaxReplicas              types.Int64 `tfsdk:"current_max_replicas"`
+	CurrentTargetCPUUtilization     types.Int64 `tfsdk:"current_target_cpu_utilization"`
+	RecommendedMinReplicas          types.Int64 `tfsdk:"recommended_min_replicas"`
+	RecommendedMaxReplicas          types.Int64 `tfsdk:"recommended_max_replicas"`
+	RecommendedTargetCPUUtilization types.Int64 `tfsdk:"recommended_target_cpu_utilization"`
+}
+
+// hpaSchemaAttribute defines the hpa attribute of the container data source.
+func hpaSchemaAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Computed:    true,
+		Description: "HorizontalPodAutoscaler recommendation for the controller. Null when Densify has no HPA data for it.",
+		Attributes: map[string]schema.Attribute{
+			"current_min_replicas": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Current HPA minimum replicas.",
+			},
+			"current_max_replicas": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Current HPA maximum replicas.",
+			},
+			"current_target_cpu_utilization": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Current HPA target CPU utilization (percentage of the CPU request).",
+			},
+			"recommended_min_replicas": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Recommended HPA minimum replicas.",
+			},
+			"recommended_max_replicas": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Recommended HPA maximum replicas.",
+			},
+			"recommended_target_cpu_utilization": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Recommended HPA target CPU utilization (percentage of the recommended CPU request).",
+			},
+		},
+	}
+}
+
+// newHPAModel maps the HPA values of a pod recommendation, or returns nil if the controller has no HPA.
+func newHPAModel(podReco *densify.DensifyRecommendation) *densifyDataSourceHPAModel {
+	if podReco.HpaMaxReplicas == 0 && podReco.RecommendedHpaMaxReplicas == 0 {
+		return nil
+	}
+	return &densifyDataSourceHPAModel{
+		CurrentMinReplicas:              types.Int64Value(int64(podReco.HpaMinReplicas)),
+		CurrentMaxReplicas:              types.Int64Value(int64(podReco.HpaMaxReplicas)),
+		CurrentTargetCPUUtilization:     types.Int64Value(int64(podReco.HpaTargetCpu)),
+		RecommendedMinReplicas:          types.Int64Value(int64(hpaValue(podReco.RecommendedHpaMinReplicas, podReco.HpaMinReplicas))),
+		RecommendedMaxReplicas:          types.Int64Value(int64(hpaValue(podReco.RecommendedHpaMaxReplicas, podReco.HpaMaxReplicas))),
+		RecommendedTargetCPUUtilization: types.Int64Value(int64(hpaValue(podReco.RecommendedHpaTargetCpu, podReco.HpaTargetCpu))),
+	}
+}
+
+// hpaValue returns the recommended value when Densify provides one, otherwise the current value.
+func hpaValue(recommended int, current int) int {
+	if recommended > 0 {
+		return recommended
+	}
+	return current
+}
+
+// hpaThresholdWarning checks how the recommended CPU requests (in millicores, summed over the pod's containers)
+// move the effective HPA scaling threshold, and returns a warning message when it shifts materially.
+func hpaThresholdWarning(podReco *densify.DensifyRecommendation, currentCPURequest int, recommendedCPURequest int) (string, bool) {
+	if podReco.HpaTargetCpu <= 0 || currentCPURequest <= 0 || recommendedCPURequest <= 0 {
+		return "", false
+	}
+	target := hpaValue(podReco.RecommendedHpaTargetCpu, podReco.HpaTargetCpu)
+	current := float64(podReco.HpaTargetCpu) * float64(currentCPURequest) / 100
+	recommended := float64(target) * float64(recommendedCPURequest) / 100
+	shift := (recommended - current) / current
+	if math.Abs(shift) <= hpaThresholdTolerance {
+		return "", false
+	}
+
+	preserving := int(math.Round(current / float64(recommendedCPURequest) * 100))
+	return fmt.Sprintf("The recommended CPU requests (%dm per pod) change the effective HPA scaling threshold from %.0fm to %.0fm per pod (%+.0f%%) at a target CPU utilization of %d%%. "+
+		"Scaling will happen at a different load than today; set the HPA target CPU utilization to about %d%% to keep the current threshold.",
+		recommendedCPURequest, current, recommended, shift*100, target, preserving), true
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+func TestHPAThresholdWarning(t *testing.T) {
+	tests := []struct {
+		name        string
+		reco        densify.DensifyRecommendation
+		current     int
+		recommended int
+		wantWarning bool
+		wantTarget  string
+	}{
+		{"no hpa", densify.DensifyRecommendation{}, 1000, 500, false, ""},
+		{"no current request", densify.DensifyRecommendation{HpaTargetCpu: 70}, 0, 500, false, ""},
+		{"no recommended request", densify.DensifyRecommendation{HpaTargetCpu: 70}, 1000, 0, false, ""},
+		{"within tolerance", densify.DensifyRecommendation{HpaTargetCpu: 70}, 1000, 900, false, ""},
+		{"lower threshold", densify.DensifyRecommendation{HpaTargetCpu: 70}, 1000, 500, true, "about 140%"},
+		{"higher threshold", densify.DensifyRecommendation{HpaTargetCpu: 50}, 400, 800, true, "about 25%"},
+		{"recommended target keeps threshold", densify.DensifyRecommendation{HpaTargetCpu: 70, RecommendedHpaTargetCpu: 60}, 1000, 1200, false, ""},
+		{"recommended target shifts threshold", densify.DensifyRecommendation{HpaTargetCpu: 70, RecommendedHpaTargetCpu: 90}, 1000, 1200, true, "about 58%"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message, warning := hpaThresholdWarning(&test.reco, test.current, test.recommended)
+			if warning != test.wantWarning {
+				t.Fatalf("hpaThresholdWarning() = %t (%q), want %t", warning, message, test.wantWarning)
+			}
+			if !strings.Contains(message, test.wantTarget) {
+				t.Errorf("hpaThresholdWarning() message = %q, want it to contain %q", message, test.wantTarget)
+			}
+		})
+	}
+}
+
+func TestNewHPAModel(t *testing.T) {
+	if got := newHPAModel(&densify.DensifyRecommendation{}); got != nil {
+		t.Errorf("newHPAModel() = %v, want nil without HPA data", got)
+	}
+	got := newHPAModel(&densify.DensifyRecommendation{HpaMinReplicas: 2, HpaMaxReplicas: 10, HpaTargetCpu: 70, RecommendedHpaMaxReplicas: 6})
+	if got == nil {
+		t.Fatal("newHPAModel() = nil, want a model")
+	}
+	if got.RecommendedMinReplicas.ValueInt64() != 2 || got.RecommendedMaxReplicas.ValueInt64() != 6 || got.RecommendedTargetCPUUtilization.ValueInt64() != 70 {
+		t.Errorf("newHPAModel() recommended values = %v, want the current values where Densify has none", got)
+	}
+}
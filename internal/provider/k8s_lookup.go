@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+// k8sPlatform is the Densify technology platform of the container data sources, whatever the provider tech_platform.
+const k8sPlatform = "kubernetes"
+
+// podTemplateHash matches the pod-template-hash suffix that Deployments add to their ReplicaSet names.
+// Kubernetes encodes the hash with a reduced alphabet (no vowels, 0, 1 or 3) to avoid generating words.
+var podTemplateHash = regexp.MustCompile(`^[bcdfghjklmnpqrstvwxz2456789]{1,10}$`)
+
+// cronJobSchedule matches the scheduled time suffix (in minutes since epoch) that CronJobs add to their Job names.
+var cronJobSchedule = regexp.MustCompile(`^[0-9]{1,12}$`)
+
+// k8sClient returns a copy of the provider recommendation source that queries Kubernetes recommendations.
+// The provider cluster and namespace are required, since the container lookups are scoped to them.
+func k8sClient(client RecommendationSource, settings *DensifySettings) (RecommendationSource, error) {
+	c, err := client.WithQuery(func(query *densify.DensifyAPIQuery) {
+		query.AnalysisTechnology = k8sPlatform
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c.Query().K8sCluster == "" {
+		return nil, fmt.Errorf("the Kubernetes cluster is not set: %s", settings.describe("cluster"))
+	}
+	if c.Query().K8sNamespace == "" {
+		return nil, fmt.Errorf("the Kubernetes namespace is not set: %s", settings.describe("namespace"))
+	}
+	return c, nil
+}
+
+// densifyOwnerReferenceModel maps an owner reference of a Kubernetes object (ex. the metadata.owner_references of a pod).
+type densifyOwnerReferenceModel struct {
+	Kind types.String `tfsdk:"kind"`
+	Name types.String `tfsdk:"name"`
+}
+
+// k8sOwner identifies a controller by its Densify controller type and name.
+type k8sOwner struct {
+	controllerType string
+	name           string
+}
+
+// ownerReferenceSchemaAttribute defines the owner_reference argument of the container data source.
+func ownerReferenceSchemaAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional:    true,
+		Description: "Look up the controller from an owner reference of the pod instead of the provider controller_type and pod_name. A ReplicaSet resolves to its Deployment and a Job to its CronJob, regardless of the generated hash suffixes.",
+		Attributes: map[string]schema.Attribute{
+			"kind": schema.StringAttribute{
+				Required:    true,
+				Description: "Kind of the owner. Ex. ReplicaSet, StatefulSet, DaemonSet, Job.",
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the owner. Ex. web-7d9c8f.",
+			},
+		},
+	}
+}
+
+// ownerCandidates returns the controllers that may own a pod through the given owner reference, most specific first.
+// Deployments own their pods through a ReplicaSet named {deployment}-{pod-template-hash}, and CronJobs through a
+// Job named {cronjob}-{scheduled time}.
+func ownerCandidates(kind string, name string) []k8sOwner {
+	kind = strings.ToLower(kind)
+	candidates := []k8sOwner{}
+	i := strings.LastIndex(name, "-")
+	switch {
+	case kind == "replicaset" && i > 0 && podTemplateHash.MatchString(name[i+1:]):
+		candidates = append(candidates, k8sOwner{"deployment", name[:i]})
+	case kind == "job" && i > 0 && cronJobSchedule.MatchString(name[i+1:]):
+		candidates = append(candidates, k8sOwner{"cronjob", name[:i]})
+	}
+	return append(candidates, k8sOwner{kind, name})
+}
+
+// isOwner reports whether a recommendation is for the given controller.
+func isOwner(reco *densify.DensifyRecommendation, owner k8sOwner) bool {
+	return strings.EqualFold(reco.ControllerType, owner.controllerType) && reco.PodService == owner.name
+}
+
+// matchesLabels reports whether the controller of a recommendation has all the given labels.
+func matchesLabels(reco *densify.DensifyRecommendation, labels map[string]types.String) bool {
+	for key, value := range labels {
+		v, ok := reco.Labels[key]
+		if !ok || v != value.ValueString() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+func TestPodTemplateHash(t *testing.T) {
+	tests := []struct {
+		suffix string
+		want   bool
+	}{
+		{"7d9c8f6b5", true},
+		{"5fbc4c6d7b", true},
+		{"x2v9", true},
+		{"api", false},
+		{"worker1", false},
+		{"a3b", false},
+		{"5fbc4c6d7b5", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		t.Run(test.suffix, func(t *testing.T) {
+			if got := podTemplateHash.MatchString(test.suffix); got != test.want {
+				t.Errorf("podTemplateHash.MatchString(%q) = %t, want %t", test.suffix, got, test.want)
+			}
+		})
+	}
+}
+
+func TestOwnerCandidates(t *testing.T) {
+	tests := []struct {
+		kind string
+		name string
+		want []k8sOwner
+	}{
+		{"ReplicaSet", "web-7d9c8f6b5", []k8sOwner{{"deployment", "web"}, {"replicaset", "web-7d9c8f6b5"}}},
+		{"ReplicaSet", "checkout-api-5fbc4c6d7b", []k8sOwner{{"deployment", "checkout-api"}, {"replicaset", "checkout-api-5fbc4c6d7b"}}},
+		{"ReplicaSet", "web-api", []k8sOwner{{"replicaset", "web-api"}}},
+		{"Job", "backup-28471230", []k8sOwner{{"cronjob", "backup"}, {"job", "backup-28471230"}}},
+		{"Job", "db-migrate", []k8sOwner{{"job", "db-migrate"}}},
+		{"StatefulSet", "redis-7d9c8f6b5", []k8sOwner{{"statefulset", "redis-7d9c8f6b5"}}},
+		{"ReplicaSet", "-7d9c8f6b5", []k8sOwner{{"replicaset", "-7d9c8f6b5"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.kind+"/"+test.name, func(t *testing.T) {
+			if got := ownerCandidates(test.kind, test.name); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ownerCandidates(%q, %q) = %v, want %v", test.kind, test.name, got, test.want)
+			}
+		})
+	}
+}
+
+func TestMatchesLabels(t *testing.T) {
+	reco := &densify.DensifyRecommendation{Labels: map[string]string{"app": "web", "tier": "frontend"}}
+	tests := []struct {
+		name   string
+		labels map[string]types.String
+		want   bool
+	}{
+		{"no labels", nil, true},
+		{"subset", map[string]types.String{"app": types.StringValue("web")}, true},
+		{"all", map[string]types.String{"app": types.StringValue("web"), "tier": types.StringValue("frontend")}, true},
+		{"different value", map[string]types.String{"app": types.StringValue("api")}, false},
+		{"missing label", map[string]types.String{"team": types.StringValue("shop")}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := matchesLabels(reco, test.labels); got != test.want {
+				t.Errorf("matchesLabels() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,341 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &densifyDataSourceK8sNodeGroup{}
+	_ datasource.DataSourceWithConfigure = &densifyDataSourceK8sNodeGroup{}
+)
+
+// NewDensifyDataSourceK8sNodeGroup is a helper function to simplify the provider implementation.
+func NewDensifyDataSourceK8sNodeGroup() datasource.DataSource {
+	return &densifyDataSourceK8sNodeGroup{}
+}
+
+// densifyDataSourceK8sNodeGroup is the data source implementation.
+type densifyDataSourceK8sNodeGroup struct {
+	provider *densifyProviderData
+}
+
+// densifyDataSourceK8sNodeGroupModel maps Kubernetes node group Recommendation schema data.
+type densifyDataSourceK8sNodeGroupModel struct {
+	// lookup arguments
+	AccountNumber types.String `tfsdk:"account_number"`
+	AccountName   types.String `tfsdk:"account_name"`
+	Platform      types.String `tfsdk:"platform"`
+	Cluster       types.String `tfsdk:"cluster"`
+	NodeGroup     types.String `tfsdk:"node_group"`
+
+	EntityId         types.String  `tfsdk:"entity_id"`
+	Name             types.String  `tfsdk:"name"`
+	ResourceId       types.String  `tfsdk:"resource_id"`
+	OptimizationType types.String  `tfsdk:"optimization_type"`
+	ApprovalType     types.String  `tfsdk:"approval_type"`
+	SavingsEstimate  types.Float64 `tfsdk:"savings_estimate"`
+	EffortEstimate   types.String  `tfsdk:"effort_estimate"`
+
+	CurrentInstance     types.String `tfsdk:"current_type"`
+	RecommendedInstance types.String `tfsdk:"recommended_type"`
+	ApprovedInstance    types.String `tfsdk:"approved_type"`
+
+	CurrentMinNodes         types.Int64 `tfsdk:"current_min_nodes"`
+	RecommendedMinNodes     types.Int64 `tfsdk:"recommended_min_nodes"`
+	CurrentMaxNodes         types.Int64 `tfsdk:"current_max_nodes"`
+	RecommendedMaxNodes     types.Int64 `tfsdk:"recommended_max_nodes"`
+	CurrentDesiredNodes     types.Int64 `tfsdk:"current_desired_nodes"`
+	RecommendedDesiredNodes types.Int64 `tfsdk:"recommended_desired_nodes"`
+}
+
+// Metadata returns the data source type name.
+func (d *densifyDataSourceK8sNodeGroup) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_k8s_node_group"
+}
+
+// Schema defines the schema for the data source.
+func (d *densifyDataSourceK8sNodeGroup) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches a Recommendation for a Kubernetes node group (EKS node group, AKS node pool, GKE node pool) from the Densify API.",
+		Attributes: map[string]schema.Attribute{
+			"account_number": accountNumberSchemaAttribute(),
+			"account_name":   accountNameSchemaAttribute(),
+			"platform": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Cloud platform of the cluster. Defaults to the provider tech_platform. Accepted values are: aws (EKS), azure (AKS), gcp (GKE).",
+			},
+			"cluster": schema.StringAttribute{
+				Required:    true,
+				Description: "The Kubernetes cluster name.",
+			},
+			"node_group": schema.StringAttribute{
+				Required:    true,
+				Description: "The node group (EKS) or node pool (AKS/GKE) name.",
+			},
+
+			"entity_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for cloud resource.",
+			},
+			"name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the Auto Scaling Group, Virtual Machine Scale Set or Managed Instance Group backing the node group.",
+			},
+			"resource_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Cloud resource ID (or ARN) of the group backing the node group.",
+			},
+			"optimization_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Type of optimization. Ex. Downsize, Upsize, Terminate, etc.",
+			},
+			"approval_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Approval type. If ITSM integration has been enabled, this field will identify whether the recommendation has been reviewed & approved.",
+			},
+			"savings_estimate": schema.Float64Attribute{
+				Computed:    true,
+				Description: "Estimated monthly savings by applying the optimization recommendation.",
+			},
+			"effort_estimate": schema.StringAttribute{
+				Computed:    true,
+				Description: "Estimated effort required by applying optimization recommendation. Ex. none, low, med, high.",
+			},
+			"current_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Current node instance type (VM size or machine type).",
+			},
+			"recommended_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Recommended node instance type generated by Densify. Use it for aws_eks_node_group instance_types, azurerm_kubernetes_cluster_node_pool vm_size or google_container_node_pool machine_type.",
+			},
+			"approved_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "The approved node instance type. This starts with the fallback instance or the current instance type, and may only be replaced by the recommended instance if 'Approval_Type' is set.",
+			},
+			"current_min_nodes": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Current minimum number of nodes.",
+			},
+			"recommended_min_nodes": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Recommended minimum number of nodes (scaling_config min_size, min_count or autoscaling min_node_count). The current number when Densify has no group recommendation.",
+			},
+			"current_max_nodes": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Current maximum number of nodes.",
+			},
+			"recommended_max_nodes": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Recommended maximum number of nodes (scaling_config max_size, max_count or autoscaling max_node_count). The current number when Densify has no group recommendation.",
+			},
+			"current_desired_nodes": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Current average number of nodes running.",
+			},
+			"recommended_desired_nodes": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Recommended number of nodes (scaling_config desired_size or node_count), within the recommended minimum and maximum. Based on the current average number of nodes when Densify has no group recommendation.",
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *densifyDataSourceK8sNodeGroup) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	tflog.Trace(ctx, "Configuring Densify API client")
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*densifyProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *densifyProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = providerData
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *densifyDataSourceK8sNodeGroup) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Trace(ctx, "Reading Densify API client")
+	ctx, span := startSpan(ctx, "densify_k8s_node_group.Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+	var state densifyDataSourceK8sNodeGroupModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	source, ok := d.provider.readSource(ctx, req, resp, cloudAttributes)
+	if !ok {
+		return
+	}
+
+	platform, err := cloudPlatform(&d.provider.settings, state.Platform)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("platform"),
+			"Invalid Node Group Platform",
+			err.Error(),
+		)
+		return
+	}
+	state.Platform = types.StringValue(platform)
+
+	client, err := source.WithQuery(func(query *densify.DensifyAPIQuery) {
+		setAccount(query, state.AccountNumber, state.AccountName)
+		query.AnalysisTechnology = platform
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create Densify query",
+			"Densify Client Query Error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Densify API client: calling GetAccountOrCluster")
+	client, _, err = d.provider.accounts.resolve(ctx, client)
+	if err != nil {
+		if client.Query().SkipErrors {
+			// skip the error message
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Account Number/Name",
+			err.Error()+d.provider.settings.describeAccount(state.AccountNumber, state.AccountName),
+		)
+		return
+	}
+	tflog.Trace(ctx, "Densify API client: GetAccountOrCluster: success")
+	tflog.Debug(ctx, "Densify API client: calling GetDensifyRecommendations")
+	recos, err := client.Recommendations()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Recommendation",
+			err.Error(),
+		)
+		return
+	}
+	tflog.Trace(ctx, "Densify API client: GetDensifyRecommendations: success")
+
+	cluster := state.Cluster.ValueString()
+	nodeGroup := state.NodeGroup.ValueString()
+	matches := filterRecommendations(recos, func(reco *densify.DensifyRecommendation) bool {
+		return isNodeGroup(reco, platform, cluster, nodeGroup)
+	})
+	tflog.Debug(ctx, fmt.Sprintf(`Num of matching node group recommendations: %d`, len(matches)))
+	if len(matches) > 1 {
+		resp.Diagnostics.AddError(
+			"Ambiguous Densify Recommendation",
+			fmt.Sprintf("Found %d recommendations for node group %q of cluster %q. Set account_number to select a single account.", len(matches), nodeGroup, cluster),
+		)
+		return
+	}
+	if len(matches) == 0 {
+		if client.Query().SkipErrors {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Recommendation",
+			fmt.Sprintf("No %s recommendation matches node group %q of cluster %q.", platform, nodeGroup, cluster),
+		)
+		return
+	}
+	reco := matches[0]
+
+	// Map response body to model
+	state.EntityId = types.StringValue(reco.EntityId)
+	state.Name = types.StringValue(reco.Name)
+	state.ResourceId = types.StringValue(reco.ResourceId)
+	state.OptimizationType = types.StringValue(reco.RecommendationType)
+	state.ApprovalType = types.StringValue(reco.ApprovalType)
+	state.SavingsEstimate = types.Float64Value(float64(reco.SavingsEstimate))
+	state.EffortEstimate = types.StringValue(reco.EffortEstimate)
+	state.CurrentInstance = types.StringValue(reco.CurrentType)
+	state.RecommendedInstance = types.StringValue(reco.RecommendedType)
+	state.ApprovedInstance = types.StringValue(client.ApprovedType(&reco))
+
+	minNodes, maxNodes, desired := recommendedGroupSizes(&reco)
+	state.CurrentMinNodes = types.Int64Value(int64(reco.MinGroupCurrent))
+	state.RecommendedMinNodes = types.Int64Value(minNodes)
+	state.CurrentMaxNodes = types.Int64Value(int64(reco.MaxGroupCurrent))
+	state.RecommendedMaxNodes = types.Int64Value(maxNodes)
+	state.CurrentDesiredNodes = types.Int64Value(int64(math.Ceil(float64(reco.AvgInstanceCountCurrent))))
+	state.RecommendedDesiredNodes = types.Int64Value(desired)
+
+	recordResult(ctx, sourceResult(client))
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// isNodeGroup reports whether a recommendation is for the group backing a Kubernetes node group, using the
+// tags (labels) that EKS, AKS and GKE put on their Auto Scaling Groups, Scale Sets and Managed Instance Groups.
+// The members of a group carry the same tags, so only group recommendations match.
+func isNodeGroup(reco *densify.DensifyRecommendation, platform string, cluster string, nodeGroup string) bool {
+	if !isGroupRecommendation(reco, platform) {
+		return false
+	}
+	switch platform {
+	case "aws":
+		if reco.Tags["eks:cluster-name"] == cluster && reco.Tags["eks:nodegroup-name"] == nodeGroup {
+			return true
+		}
+		// self-managed node groups are tagged with the cluster ownership tag only.
+		return reco.Tags["kubernetes.io/cluster/"+cluster] == "owned" && reco.Tags["Name"] == nodeGroup
+	case "azure":
+		if reco.Tags["aks-managed-poolName"] != nodeGroup {
+			return false
+		}
+		if clusterName, ok := reco.Tags["aks-managed-cluster-name"]; ok {
+			return clusterName == cluster
+		}
+		// clusters created before AKS tagged the cluster name use the default node resource group,
+		// named MC_{resource group}_{cluster}_{location}.
+		resourceGroup := strings.ToLower(parseResourcePath(reco.ResourceId).get("resourceGroups"))
+		return strings.HasPrefix(resourceGroup, "mc_") && strings.Contains(resourceGroup, "_"+strings.ToLower(cluster)+"_")
+	case "gcp":
+		return reco.Tags["goog-k8s-cluster-name"] == cluster && reco.Tags["goog-k8s-node-pool-name"] == nodeGroup
+	}
+	return false
+}
+
+// isGroupRecommendation reports whether a recommendation is for an Auto Scaling Group, a Scale Set or a Managed
+// Instance Group rather than one of its instances.
+func isGroupRecommendation(reco *densify.DensifyRecommendation, platform string) bool {
+	switch platform {
+	case "aws":
+		return isAutoScalingGroup(reco)
+	case "azure":
+		resourceID := parseResourcePath(reco.ResourceId)
+		return resourceID.get("virtualMachineScaleSets") != "" && resourceID.get("virtualMachines") == ""
+	case "gcp":
+		return parseResourcePath(reco.ResourceId).get("instanceGroupManagers") != ""
+	}
+	return false
+}
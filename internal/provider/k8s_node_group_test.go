@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+func TestIsNodeGroup(t *testing.T) {
+	const (
+		eksGroup   = "arn:aws:autoscaling:us-east-1:123456789012:autoScalingGroup:0b5e7a3c:autoScalingGroupName/eks-workers-0b5e7a3c"
+		eksNode    = "arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0"
+		aksGroup   = "/subscriptions/0000/resourceGroups/MC_rg_prod_eastus/providers/Microsoft.Compute/virtualMachineScaleSets/aks-workers-123-vmss"
+		aksNode    = "/subscriptions/0000/resourceGroups/MC_rg_prod_eastus/providers/Microsoft.Compute/virtualMachineScaleSets/aks-workers-123-vmss/virtualMachines/0"
+		aksCustom  = "/subscriptions/0000/resourceGroups/prod-nodes/providers/Microsoft.Compute/virtualMachineScaleSets/aks-workers-123-vmss"
+		gkeGroup   = "projects/p/zones/us-central1-a/instanceGroupManagers/gke-prod-workers-1234-grp"
+		gkeRegion  = "https://www.googleapis.com/compute/v1/projects/p/regions/us-central1/instanceGroupManagers/gke-prod-workers-1234-grp"
+		gkeNode    = "projects/p/zones/us-central1-a/instances/gke-prod-workers-1234-abcd"
+		nodeGroup  = "workers"
+		clusterTag = "prod"
+	)
+	eksTags := map[string]string{"eks:cluster-name": clusterTag, "eks:nodegroup-name": nodeGroup}
+	selfManagedTags := map[string]string{"kubernetes.io/cluster/prod": "owned", "Name": nodeGroup}
+	aksTags := map[string]string{"aks-managed-poolName": nodeGroup, "aks-managed-cluster-name": clusterTag}
+	aksUntagged := map[string]string{"aks-managed-poolName": nodeGroup}
+	gkeTags := map[string]string{"goog-k8s-cluster-name": clusterTag, "goog-k8s-node-pool-name": nodeGroup}
+	tests := []struct {
+		name     string
+		platform string
+		reco     densify.DensifyRecommendation
+		want     bool
+	}{
+		{"eks group", "aws", densify.DensifyRecommendation{ResourceId: eksGroup, Tags: eksTags}, true},
+		{"eks group by service type", "aws", densify.DensifyRecommendation{ServiceType: "ASG", Tags: eksTags}, true},
+		{"eks node", "aws", densify.DensifyRecommendation{ResourceId: eksNode, ServiceType: "EC2", Tags: eksTags}, false},
+		{"eks other node group", "aws", densify.DensifyRecommendation{ResourceId: eksGroup, Tags: map[string]string{"eks:cluster-name": clusterTag, "eks:nodegroup-name": "system"}}, false},
+		{"self-managed group", "aws", densify.DensifyRecommendation{ResourceId: eksGroup, Tags: selfManagedTags}, true},
+		{"self-managed node", "aws", densify.DensifyRecommendation{ResourceId: eksNode, Tags: selfManagedTags}, false},
+		{"aks scale set", "azure", densify.DensifyRecommendation{ResourceId: aksGroup, Tags: aksTags}, true},
+		{"aks scale set instance", "azure", densify.DensifyRecommendation{ResourceId: aksNode, Tags: aksTags}, false},
+		{"aks custom node resource group", "azure", densify.DensifyRecommendation{ResourceId: aksCustom, Tags: aksTags}, true},
+		{"aks other cluster", "azure", densify.DensifyRecommendation{ResourceId: aksGroup, Tags: map[string]string{"aks-managed-poolName": nodeGroup, "aks-managed-cluster-name": "dev"}}, false},
+		{"aks untagged cluster", "azure", densify.DensifyRecommendation{ResourceId: aksGroup, Tags: aksUntagged}, true},
+		{"aks untagged custom node resource group", "azure", densify.DensifyRecommendation{ResourceId: aksCustom, Tags: aksUntagged}, false},
+		{"gke zonal group", "gcp", densify.DensifyRecommendation{ResourceId: gkeGroup, Tags: gkeTags}, true},
+		{"gke regional group", "gcp", densify.DensifyRecommendation{ResourceId: gkeRegion, Tags: gkeTags}, true},
+		{"gke node", "gcp", densify.DensifyRecommendation{ResourceId: gkeNode, Tags: gkeTags}, false},
+		{"unknown platform", "oci", densify.DensifyRecommendation{ResourceId: eksGroup, Tags: eksTags}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isNodeGroup(&test.reco, test.platform, clusterTag, nodeGroup); got != test.want {
+				t.Errorf("isNodeGroup() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
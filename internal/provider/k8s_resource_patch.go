@@ -0,0 +1,376 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/joelpereira/densify-api-client-go"
+	"gopkg.in/yaml.v3"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &densifyDataSourceK8sResourcePatch{}
+	_ datasource.DataSourceWithConfigure = &densifyDataSourceK8sResourcePatch{}
+)
+
+// NewDensifyDataSourceK8sResourcePatch is a helper function to simplify the provider implementation.
+func NewDensifyDataSourceK8sResourcePatch() datasource.DataSource {
+	return &densifyDataSourceK8sResourcePatch{}
+}
+
+// densifyDataSourceK8sResourcePatch is the data source implementation.
+type densifyDataSourceK8sResourcePatch struct {
+	provider *densifyProviderData
+}
+
+// densifyDataSourceK8sResourcePatchModel maps Kubernetes resource patch schema data.
+type densifyDataSourceK8sResourcePatchModel struct {
+	// lookup arguments
+	Namespace         types.String   `tfsdk:"namespace"`
+	ControllerType    types.String   `tfsdk:"controller_type"`
+	PodName           types.String   `tfsdk:"pod_name"`
+	ExcludeContainers []types.String `tfsdk:"exclude_containers"`
+
+	Cluster types.String                           `tfsdk:"cluster"`
+	Patches map[string]densifyDataSourcePatchModel `tfsdk:"patches"`
+}
+
+// densifyDataSourcePatchModel maps the patch documents of one controller.
+type densifyDataSourcePatchModel struct {
+	ApiVersion types.String `tfsdk:"api_version"`
+	Kind       types.String `tfsdk:"kind"`
+	Namespace  types.String `tfsdk:"namespace"`
+	Name       types.String `tfsdk:"name"`
+	Yaml       types.String `tfsdk:"yaml"`
+	Json       types.String `tfsdk:"json"`
+}
+
+// k8sController describes where the pod template lives in a Kubernetes controller manifest.
+type k8sController struct {
+	apiVersion  string
+	kind        string
+	podSpecPath []string
+}
+
+// k8sControllers maps the Densify controller types to their Kubernetes manifests. Bare pods are left out, since
+// the container resources of a running pod cannot be changed by a patch.
+var k8sControllers = map[string]k8sController{
+	"deployment":  {"apps/v1", "Deployment", []string{"spec", "template", "spec"}},
+	"statefulset": {"apps/v1", "StatefulSet", []string{"spec", "template", "spec"}},
+	"daemonset":   {"apps/v1", "DaemonSet", []string{"spec", "template", "spec"}},
+	"replicaset":  {"apps/v1", "ReplicaSet", []string{"spec", "template", "spec"}},
+	"job":         {"batch/v1", "Job", []string{"spec", "template", "spec"}},
+	"cronjob":     {"batch/v1", "CronJob", []string{"spec", "jobTemplate", "spec", "template", "spec"}},
+}
+
+// Metadata returns the data source type name.
+func (d *densifyDataSourceK8sResourcePatch) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_k8s_resource_patch"
+}
+
+// Schema defines the schema for the data source.
+func (d *densifyDataSourceK8sResourcePatch) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Generates Kubernetes strategic merge patches with the Densify container recommendations of each controller, for GitOps repositories that don't manage workloads with Terraform. Bare pods are left out, since their container resources cannot be patched in place, and so are containers without a Densify recommendation.",
+		Attributes: map[string]schema.Attribute{
+			"namespace": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Only include controllers of this namespace. Defaults to the provider namespace; all namespaces when neither is set.",
+			},
+			"controller_type": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Only include controllers of this type. Ex. deployment, statefulset. Defaults to the provider controller_type; all types when neither is set.",
+			},
+			"pod_name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Only include the controller with this name. Defaults to the provider pod_name; all controllers when neither is set.",
+			},
+			"exclude_containers": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Names of containers to leave out of the patches. Injected sidecars (ex. istio-proxy) are always left out.",
+			},
+
+			"cluster": schema.StringAttribute{
+				Computed:    true,
+				Description: "The Kubernetes cluster name.",
+			},
+			"patches": schema.MapNestedAttribute{
+				Computed:    true,
+				Description: "Patches keyed by controller, in the form {kind}/{namespace}/{name}. Ex. Deployment/default/web. Controllers with nothing to patch are left out.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"api_version": schema.StringAttribute{
+							Computed:    true,
+							Description: "The Kubernetes API version of the controller. Ex. apps/v1.",
+						},
+						"kind": schema.StringAttribute{
+							Computed:    true,
+							Description: "The Kubernetes kind of the controller. Ex. Deployment.",
+						},
+						"namespace": schema.StringAttribute{
+							Computed:    true,
+							Description: "The Kubernetes namespace of the controller.",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The name of the controller.",
+						},
+						"yaml": schema.StringAttribute{
+							Computed:    true,
+							Description: "Strategic merge patch setting the recommended container resources, as YAML. Ex. for kustomize patches.",
+						},
+						"json": schema.StringAttribute{
+							Computed:    true,
+							Description: "Strategic merge patch setting the recommended container resources, as JSON. Ex. for kubectl patch --type strategic. This is not an RFC 6902 JSON Patch, so it cannot be used with kubectl patch --type json.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *densifyDataSourceK8sResourcePatch) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	tflog.Trace(ctx, "Configuring Densify API client")
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*densifyProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *densifyProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = providerData
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *densifyDataSourceK8sResourcePatch) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Trace(ctx, "Reading Densify API client")
+	ctx, span := startSpan(ctx, "densify_k8s_resource_patch.Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+	var state densifyDataSourceK8sResourcePatchModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	source, ok := d.provider.readSource(ctx, req, resp, k8sAttributes)
+	if !ok {
+		return
+	}
+
+	client, err := source.WithQuery(func(query *densify.DensifyAPIQuery) {
+		query.AnalysisTechnology = k8sPlatform
+		if !state.Namespace.IsNull() {
+			query.K8sNamespace = state.Namespace.ValueString()
+		}
+		if !state.ControllerType.IsNull() {
+			query.K8sControllerType = state.ControllerType.ValueString()
+		}
+		if !state.PodName.IsNull() {
+			query.K8sPodName = state.PodName.ValueString()
+		}
+		query.K8sContainerName = ""
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create Densify query",
+			"Densify Client Query Error: "+err.Error(),
+		)
+		return
+	}
+	state.Namespace = types.StringValue(client.Query().K8sNamespace)
+	state.ControllerType = types.StringValue(client.Query().K8sControllerType)
+	state.PodName = types.StringValue(client.Query().K8sPodName)
+	state.Cluster = types.StringValue(client.Query().K8sCluster)
+
+	tflog.Debug(ctx, "Densify API client: calling GetAccountOrCluster")
+	client, _, err = d.provider.accounts.resolve(ctx, client)
+	if err != nil {
+		if client.Query().SkipErrors {
+			// skip the error message
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Account Number/Name",
+			err.Error()+d.provider.settings.explain("cluster"),
+		)
+		return
+	}
+	tflog.Trace(ctx, "Densify API client: GetAccountOrCluster: success")
+	tflog.Debug(ctx, "Densify API client: calling GetDensifyRecommendations")
+	recos, err := client.Recommendations()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Find Densify Recommendation",
+			err.Error(),
+		)
+		return
+	}
+	tflog.Trace(ctx, "Densify API client: GetDensifyRecommendations: success")
+
+	matches := filterRecommendations(recos, func(reco *densify.DensifyRecommendation) bool {
+		return matchesFilter(reco.Namespace, client.Query().K8sNamespace) &&
+			matchesFilter(reco.ControllerType, client.Query().K8sControllerType) &&
+			matchesFilter(reco.PodService, client.Query().K8sPodName)
+	})
+	tflog.Debug(ctx, fmt.Sprintf(`Num of matching controller recommendations: %d`, len(matches)))
+
+	excluded := map[string]bool{}
+	for _, name := range state.ExcludeContainers {
+		excluded[name.ValueString()] = true
+	}
+
+	state.Patches = map[string]densifyDataSourcePatchModel{}
+	for i := range matches {
+		reco := &matches[i]
+		controller, ok := k8sControllers[strings.ToLower(reco.ControllerType)]
+		if !ok {
+			// bare pods land here too: their container resources cannot be patched in place.
+			tflog.Debug(ctx, fmt.Sprintf(`Skipping unsupported controller type %q of %s`, reco.ControllerType, reco.PodService))
+			continue
+		}
+		patch := newResourcePatch(reco, controller, excluded)
+		if patch == nil {
+			continue
+		}
+
+		yamlPatch, err := yaml.Marshal(patch)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Generate Kubernetes Patch",
+				err.Error(),
+			)
+			return
+		}
+		jsonPatch, err := json.Marshal(patch)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Generate Kubernetes Patch",
+				err.Error(),
+			)
+			return
+		}
+
+		key := fmt.Sprintf("%s/%s/%s", controller.kind, reco.Namespace, reco.PodService)
+		state.Patches[key] = densifyDataSourcePatchModel{
+			ApiVersion: types.StringValue(controller.apiVersion),
+			Kind:       types.StringValue(controller.kind),
+			Namespace:  types.StringValue(reco.Namespace),
+			Name:       types.StringValue(reco.PodService),
+			Yaml:       types.StringValue(string(yamlPatch)),
+			Json:       types.StringValue(string(jsonPatch)),
+		}
+	}
+
+	recordResult(ctx, sourceResult(client))
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// matchesFilter reports whether a value matches an optional filter, ignoring case.
+func matchesFilter(value string, filter string) bool {
+	return filter == "" || strings.EqualFold(value, filter)
+}
+
+// newResourcePatch builds the strategic merge patch setting the recommended resources of the containers of a
+// controller, or returns nil if there is nothing to patch. Injected sidecars are left out, since patching them
+// would add them to the pod template, and so are containers without a recommendation, whose fallback values are
+// not Densify recommendations.
+func newResourcePatch(reco *densify.DensifyRecommendation, controller k8sController, excluded map[string]bool) map[string]interface{} {
+	containers := []interface{}{}
+	initContainers := []interface{}{}
+	for i := range reco.Containers {
+		container := &reco.Containers[i]
+		kind := containerKind(reco, container)
+		if kind == containerKindSidecar || excluded[container.Container] || !hasRecommendation(container) {
+			continue
+		}
+
+		c := newContainerModel(container, kind, false)
+		resources := map[string]interface{}{}
+		if len(c.ResourcesRequests) > 0 {
+			resources["requests"] = resourceValues(c.ResourcesRequests)
+		}
+		if len(c.ResourcesLimits) > 0 {
+			resources["limits"] = resourceValues(c.ResourcesLimits)
+		}
+		if len(resources) == 0 {
+			continue
+		}
+
+		patch := map[string]interface{}{
+			"name":      container.Container,
+			"resources": resources,
+		}
+		if kind == containerKindInit {
+			initContainers = append(initContainers, patch)
+		} else {
+			containers = append(containers, patch)
+		}
+	}
+	if len(containers) == 0 && len(initContainers) == 0 {
+		return nil
+	}
+
+	podSpec := map[string]interface{}{}
+	if len(containers) > 0 {
+		podSpec["containers"] = containers
+	}
+	if len(initContainers) > 0 {
+		podSpec["initContainers"] = initContainers
+	}
+
+	patch := map[string]interface{}{
+		"apiVersion": controller.apiVersion,
+		"kind":       controller.kind,
+		"metadata": map[string]interface{}{
+			"name":      reco.PodService,
+			"namespace": reco.Namespace,
+		},
+	}
+	// nest the pod spec in the controller's path to the pod template
+	parent := patch
+	last := len(controller.podSpecPath) - 1
+	for _, key := range controller.podSpecPath[:last] {
+		child := map[string]interface{}{}
+		parent[key] = child
+		parent = child
+	}
+	parent[controller.podSpecPath[last]] = podSpec
+	return patch
+}
+
+// resourceValues converts a requests or limits map to plain strings for the patch documents.
+func resourceValues(resources map[string]types.String) map[string]string {
+	values := map[string]string{}
+	for name, v := range resources {
+		values[name] = v.ValueString()
+	}
+	return values
+}
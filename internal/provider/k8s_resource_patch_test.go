@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+func TestNewResourcePatch(t *testing.T) {
+	reco := &densify.DensifyRecommendation{
+		PodService: "web",
+		Namespace:  "shop",
+		Containers: []densify.DensifyContainerRecommendation{
+			{Container: "app", RecommendedCpuRequest: 250, RecommendedCpuLimit: 500, RecommendedMemRequest: 256, RecommendedMemLimit: 512},
+			{Container: "istio-proxy", RecommendedCpuRequest: 100},
+			{Container: "migrate", ContainerType: "init", RecommendedCpuRequest: 100, RecommendedCpuLimit: 200, RecommendedMemRequest: 64, RecommendedMemLimit: 128},
+		},
+	}
+	requestsOnly := &densify.DensifyRecommendation{
+		PodService: "worker",
+		Namespace:  "shop",
+		Containers: []densify.DensifyContainerRecommendation{
+			{Container: "app", CurrentCpuLimit: 1000, CurrentMemLimit: 1024, RecommendedCpuRequest: 250, RecommendedMemRequest: 256},
+		},
+	}
+	tests := []struct {
+		name       string
+		reco       *densify.DensifyRecommendation
+		controller string
+		excluded   map[string]bool
+		want       string
+	}{
+		{
+			name:       "deployment",
+			reco:       reco,
+			controller: "deployment",
+			excluded:   map[string]bool{"migrate": true},
+			want:       `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","namespace":"shop"},"spec":{"template":{"spec":{"containers":[{"name":"app","resources":{"limits":{"cpu":"500m","memory":"512Mi"},"requests":{"cpu":"250m","memory":"256Mi"}}}]}}}}`,
+		},
+		{
+			name:       "cronjob",
+			reco:       reco,
+			controller: "cronjob",
+			excluded:   map[string]bool{"app": true},
+			want:       `{"apiVersion":"batch/v1","kind":"CronJob","metadata":{"name":"web","namespace":"shop"},"spec":{"jobTemplate":{"spec":{"template":{"spec":{"initContainers":[{"name":"migrate","resources":{"limits":{"cpu":"200m","memory":"128Mi"},"requests":{"cpu":"100m","memory":"64Mi"}}}]}}}}}}`,
+		},
+		{
+			name:       "all containers excluded",
+			reco:       reco,
+			controller: "deployment",
+			excluded:   map[string]bool{"app": true, "migrate": true},
+			want:       `null`,
+		},
+		{
+			name: "no recommendation",
+			reco: &densify.DensifyRecommendation{PodService: "batch", Namespace: "shop", Containers: []densify.DensifyContainerRecommendation{
+				{Container: "app", CurrentCpuRequest: 100, FallbackCpuRequest: "200m", FallbackMemLimit: "1Gi"},
+			}},
+			controller: "deployment",
+			want:       `null`,
+		},
+		{
+			name: "container without a recommendation",
+			reco: &densify.DensifyRecommendation{PodService: "web", Namespace: "shop", Containers: []densify.DensifyContainerRecommendation{
+				{Container: "app", RecommendedCpuRequest: 250},
+				{Container: "worker", FallbackCpuRequest: "200m"},
+			}},
+			controller: "deployment",
+			want:       `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","namespace":"shop"},"spec":{"template":{"spec":{"containers":[{"name":"app","resources":{"requests":{"cpu":"250m"}}}]}}}}`,
+		},
+		{
+			name:       "agent daemonset",
+			reco:       &densify.DensifyRecommendation{PodService: "fluent-bit", Namespace: "logging", Containers: []densify.DensifyContainerRecommendation{{Container: "fluent-bit", RecommendedCpuRequest: 50, RecommendedMemRequest: 64}}},
+			controller: "daemonset",
+			want:       `{"apiVersion":"apps/v1","kind":"DaemonSet","metadata":{"name":"fluent-bit","namespace":"logging"},"spec":{"template":{"spec":{"containers":[{"name":"fluent-bit","resources":{"requests":{"cpu":"50m","memory":"64Mi"}}}]}}}}`,
+		},
+		{
+			name:       "no recommended limits",
+			reco:       requestsOnly,
+			controller: "deployment",
+			want:       `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"worker","namespace":"shop"},"spec":{"template":{"spec":{"containers":[{"name":"app","resources":{"requests":{"cpu":"250m","memory":"256Mi"}}}]}}}}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			patch := newResourcePatch(test.reco, k8sControllers[test.controller], test.excluded)
+			got, err := json.Marshal(patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != test.want {
+				t.Errorf("newResourcePatch() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestK8sControllersLeaveOutPods(t *testing.T) {
+	if _, ok := k8sControllers["pod"]; ok {
+		t.Error(`k8sControllers["pod"] is set, want bare pods left out of the patches`)
+	}
+}
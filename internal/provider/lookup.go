@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+// cloudPlatforms lists the accepted cloud platforms for data sources with a platform argument.
+var cloudPlatforms = []string{"aws", "azure", "gcp"}
+
+// cloudPlatform returns the platform argument of a data source, or the provider tech_platform when it is not set.
+func cloudPlatform(settings *DensifySettings, platform types.String) (string, error) {
+	value := settings.techPlatform
+	source := settings.describe("tech_platform")
+	if !platform.IsNull() {
+		value = platform.ValueString()
+		source = fmt.Sprintf("platform %q is set on the data source", value)
+	}
+	value = strings.ToLower(value)
+	if value == "" {
+		return "", fmt.Errorf("no platform is set (%s). Set platform on the data source or tech_platform on the provider. Accepted values are: %s", source, strings.Join(cloudPlatforms, ", "))
+	}
+	for _, p := range cloudPlatforms {
+		if value == p {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("unknown platform %q (%s). Accepted values are: %s", value, source, strings.Join(cloudPlatforms, ", "))
+}
+
+// filterRecommendations returns the recommendations accepted by match.
+func filterRecommendations(recos []densify.DensifyRecommendation, match func(reco *densify.DensifyRecommendation) bool) []densify.DensifyRecommendation {
+	matches := []densify.DensifyRecommendation{}
+	for i := range recos {
+		if match(&recos[i]) {
+			matches = append(matches, recos[i])
+		}
+	}
+	return matches
+}
+
+// matchesResourceID reports whether a recommendation is for the given cloud resource ID. IDs are compared ignoring
+// case and trailing slashes (Azure resource IDs are case insensitive), and an AWS instance ID matches its ARN.
+func matchesResourceID(reco *densify.DensifyRecommendation, id string) bool {
+	id = strings.TrimSuffix(id, "/")
+	recoID := strings.TrimSuffix(reco.ResourceId, "/")
+	if id == "" || recoID == "" {
+		return false
+	}
+	if strings.EqualFold(recoID, id) {
+		return true
+	}
+	// ex. arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0
+	return (strings.HasPrefix(recoID, "arn:") && strings.HasSuffix(recoID, "/"+id)) ||
+		(strings.HasPrefix(id, "arn:") && strings.HasSuffix(id, "/"+recoID))
+}
+
+// matchesTags reports whether a recommendation has all the given tag key/value pairs.
+func matchesTags(reco *densify.DensifyRecommendation, tags map[string]types.String) bool {
+	for key, value := range tags {
+		v, ok := reco.Tags[key]
+		if !ok || v != value.ValueString() {
+			return false
+		}
+	}
+	return true
+}
+
+// ambiguousMatches describes the recommendations matched by an ambiguous lookup, for error messages.
+func ambiguousMatches(matches []densify.DensifyRecommendation) string {
+	const maxListed = 5
+	names := []string{}
+	for i := 0; i < len(matches) && i < maxListed; i++ {
+		names = append(names, fmt.Sprintf("%s (%s)", matches[i].Name, matches[i].ResourceId))
+	}
+	if len(matches) > maxListed {
+		names = append(names, fmt.Sprintf("and %d more", len(matches)-maxListed))
+	}
+	return strings.Join(names, ", ")
+}
+
+// resourcePath holds the segments of a cloud resource ID, keyed by lower case segment name.
+// Ex. /subscriptions/{id}/resourceGroups/{rg}/providers/Microsoft.Compute/virtualMachines/{name} for Azure,
+// or projects/{project}/zones/{zone}/instances/{name} for GCP.
+type resourcePath map[string]string
+
+// parseResourcePath splits a cloud resource ID into its key/value segments.
+// GCP self links (https://www.googleapis.com/compute/v1/projects/...) are trimmed to the projects segment first.
+func parseResourcePath(id string) resourcePath {
+	if strings.HasPrefix(id, "https://") {
+		if i := strings.Index(id, "/projects/"); i > 0 {
+			id = id[i:]
+		}
+	}
+	segments := resourcePath{}
+	parts := strings.Split(strings.Trim(id, "/"), "/")
+	for i := 0; i+1 < len(parts); i += 2 {
+		segments[strings.ToLower(parts[i])] = parts[i+1]
+	}
+	return segments
+}
+
+// get returns the value of a segment, ignoring case of the segment name.
+func (id resourcePath) get(segment string) string {
+	return id[strings.ToLower(segment)]
+}
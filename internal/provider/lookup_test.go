@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+func TestMatchesResourceID(t *testing.T) {
+	const (
+		arn     = "arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0"
+		azureID = "/subscriptions/0000-1111/resourceGroups/shop-rg/providers/Microsoft.Compute/virtualMachines/web-01"
+		gcpID   = "projects/shop/zones/us-central1-a/instances/web-01"
+	)
+	tests := []struct {
+		name   string
+		recoID string
+		id     string
+		want   bool
+	}{
+		{"same ARN", arn, arn, true},
+		{"instance ID of the ARN", arn, "i-0123456789abcdef0", true},
+		{"ARN of the instance ID", "i-0123456789abcdef0", arn, true},
+		{"other instance ID", arn, "i-0fedcba9876543210", false},
+		{"instance ID prefix", arn, "i-0123456789abcdef", false},
+		{"instance IDs without ARN", "i-0123456789abcdef0", "i-0123456789abcdef0", true},
+		{"Azure ID", azureID, azureID, true},
+		{"Azure ID in another case", azureID, "/subscriptions/0000-1111/resourcegroups/SHOP-RG/providers/microsoft.compute/virtualmachines/web-01", true},
+		{"Azure ID with trailing slash", azureID, azureID + "/", true},
+		{"Azure VM name", azureID, "web-01", false},
+		{"other Azure VM", azureID, "/subscriptions/0000-1111/resourceGroups/shop-rg/providers/Microsoft.Compute/virtualMachines/web-02", false},
+		{"GCP ID", gcpID, gcpID, true},
+		{"empty ID", arn, "", false},
+		{"recommendation without ID", "", "i-0123456789abcdef0", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reco := &densify.DensifyRecommendation{ResourceId: test.recoID}
+			if got := matchesResourceID(reco, test.id); got != test.want {
+				t.Errorf("matchesResourceID(%q, %q) = %t, want %t", test.recoID, test.id, got, test.want)
+			}
+		})
+	}
+}
+
+func TestMatchesTags(t *testing.T) {
+	reco := &densify.DensifyRecommendation{Tags: map[string]string{"team": "shop", "env": "prod", "owner": ""}}
+	tests := []struct {
+		name string
+		tags map[string]types.String
+		want bool
+	}{
+		{"all tags", map[string]types.String{"team": types.StringValue("shop"), "env": types.StringValue("prod")}, true},
+		{"one tag", map[string]types.String{"env": types.StringValue("prod")}, true},
+		{"empty value", map[string]types.String{"owner": types.StringValue("")}, true},
+		{"other value", map[string]types.String{"env": types.StringValue("dev")}, false},
+		{"value in another case", map[string]types.String{"env": types.StringValue("Prod")}, false},
+		{"missing tag", map[string]types.String{"team": types.StringValue("shop"), "cost-center": types.StringValue("42")}, false},
+		{"missing tag with empty value", map[string]types.String{"cost-center": types.StringValue("")}, false},
+		{"no tags", map[string]types.String{}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := matchesTags(reco, test.tags); got != test.want {
+				t.Errorf("matchesTags(%v) = %t, want %t", test.tags, got, test.want)
+			}
+		})
+	}
+
+	if matchesTags(&densify.DensifyRecommendation{}, map[string]types.String{"env": types.StringValue("prod")}) {
+		t.Error("matchesTags() = true for a recommendation without tags, want false")
+	}
+}
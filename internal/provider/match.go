@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+// Name matching modes of the match_mode argument.
+const (
+	matchModeExact           = "exact"
+	matchModeCaseInsensitive = "case_insensitive"
+	matchModePrefix          = "prefix"
+	matchModeRegex           = "regex"
+)
+
+// matchModes lists the accepted values of the match_mode argument.
+var matchModes = []string{matchModeExact, matchModeCaseInsensitive, matchModePrefix, matchModeRegex}
+
+// maxSuggestions is the number of closest names listed when a lookup finds no recommendation.
+const maxSuggestions = 3
+
+// matchMode returns the match_mode argument, defaulting to exact.
+func matchMode(mode types.String) (string, error) {
+	if mode.IsNull() || mode.ValueString() == "" {
+		return matchModeExact, nil
+	}
+	value := strings.ToLower(mode.ValueString())
+	for _, m := range matchModes {
+		if value == m {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("unknown match_mode %q. Accepted values are: %s", mode.ValueString(), strings.Join(matchModes, ", "))
+}
+
+// nameMatcher returns a function that matches names against pattern with the given match mode. Regular
+// expressions are anchored: they must match the whole name.
+func nameMatcher(mode string, pattern string) (func(name string) bool, error) {
+	switch mode {
+	case matchModeCaseInsensitive:
+		return func(name string) bool {
+			return strings.EqualFold(name, pattern)
+		}, nil
+	case matchModePrefix:
+		return func(name string) bool {
+			return strings.HasPrefix(name, pattern)
+		}, nil
+	case matchModeRegex:
+		re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %s", pattern, err)
+		}
+		return re.MatchString, nil
+	}
+	return func(name string) bool {
+		return name == pattern
+	}, nil
+}
+
+// closestNames returns up to maxSuggestions distinct names closest to target by edit distance (ignoring case),
+// leaving out names too different to be a likely typo.
+func closestNames(target string, names []string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+	maxDistance := len(target) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	seen := map[string]bool{}
+	candidates := []candidate{}
+	for _, name := range names {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if d := editDistance(strings.ToLower(target), strings.ToLower(name)); d <= maxDistance {
+			candidates = append(candidates, candidate{name, d})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	closest := []string{}
+	for i := 0; i < len(candidates) && i < maxSuggestions; i++ {
+		closest = append(closest, candidates[i].name)
+	}
+	return closest
+}
+
+// didYouMean formats the closest names to target as a hint for "not found" diagnostics, or returns an empty string.
+func didYouMean(target string, names []string) string {
+	closest := closestNames(target, names)
+	if len(closest) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n\nDid you mean: %s?", strings.Join(closest, ", "))
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// recommendationNames returns the system names of recommendations.
+func recommendationNames(recos []densify.DensifyRecommendation) []string {
+	names := []string{}
+	for i := range recos {
+		names = append(names, recos[i].Name)
+	}
+	return names
+}
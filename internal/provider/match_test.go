@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"web", "", 3},
+		{"", "web", 3},
+		{"web", "web", 0},
+		{"kitten", "sitting", 3},
+		{"web-server", "web-sever", 1},
+		{"api", "pai", 2},
+		{"café", "cafe", 1},
+	}
+	for _, test := range tests {
+		t.Run(test.a+"/"+test.b, func(t *testing.T) {
+			if got := editDistance(test.a, test.b); got != test.want {
+				t.Errorf("editDistance(%q, %q) = %d, want %d", test.a, test.b, got, test.want)
+			}
+		})
+	}
+}
+
+func TestClosestNames(t *testing.T) {
+	names := []string{"web-server-01", "web-server-02", "web-server-03", "web-server-10", "db-server-01", "", "web-server-01"}
+	tests := []struct {
+		name   string
+		target string
+		want   []string
+	}{
+		{"typo", "web-sever-01", []string{"web-server-01", "web-server-02", "web-server-03"}},
+		{"case", "WEB-SERVER-10", []string{"web-server-10", "web-server-01", "web-server-02"}},
+		{"too different", "cache", []string{}},
+		{"short name", "db", []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := closestNames(test.target, names); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("closestNames(%q) = %v, want %v", test.target, got, test.want)
+			}
+		})
+	}
+	if got := didYouMean("cache", names); got != "" {
+		t.Errorf("didYouMean() = %q, want no hint", got)
+	}
+	if got, want := didYouMean("db-server-1", names), "\n\nDid you mean: db-server-01, web-server-01, web-server-10?"; got != want {
+		t.Errorf("didYouMean() = %q, want %q", got, want)
+	}
+}
+
+func TestNameMatcher(t *testing.T) {
+	tests := []struct {
+		mode    string
+		pattern string
+		name    string
+		want    bool
+	}{
+		{matchModeExact, "web", "web", true},
+		{matchModeExact, "web", "Web", false},
+		{matchModeCaseInsensitive, "web", "WEB", true},
+		{matchModePrefix, "web-", "web-01", true},
+		{matchModePrefix, "web-", "db-01", false},
+		{matchModeRegex, `^web-\d+$`, "web-01", true},
+		{matchModeRegex, `^web-\d+$`, "web-a", false},
+		{matchModeRegex, `web-\d+`, "web-01", true},
+		{matchModeRegex, "web", "my-web-old", false},
+		{matchModeRegex, "web|db", "db", true},
+		{matchModeRegex, "web|db", "web-01", false},
+		{matchModeRegex, "web.*", "web-01", true},
+	}
+	for _, test := range tests {
+		t.Run(test.mode+"/"+test.pattern+"/"+test.name, func(t *testing.T) {
+			match, err := nameMatcher(test.mode, test.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := match(test.name); got != test.want {
+				t.Errorf("nameMatcher(%q, %q)(%q) = %t, want %t", test.mode, test.pattern, test.name, got, test.want)
+			}
+		})
+	}
+	if _, err := nameMatcher(matchModeRegex, "web-("); err == nil {
+		t.Error("nameMatcher() error = nil, want an invalid regular expression error")
+	}
+}
+
+func TestMatchMode(t *testing.T) {
+	tests := []struct {
+		value   types.String
+		want    string
+		wantErr bool
+	}{
+		{types.StringNull(), matchModeExact, false},
+		{types.StringValue(""), matchModeExact, false},
+		{types.StringValue("Prefix"), matchModePrefix, false},
+		{types.StringValue("fuzzy"), "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.value.String(), func(t *testing.T) {
+			got, err := matchMode(test.value)
+			if (err != nil) != test.wantErr || got != test.want {
+				t.Errorf("matchMode(%s) = %q, %v, want %q, error %t", test.value, got, err, test.want, test.wantErr)
+			}
+		})
+	}
+}
@@ -2,10 +2,13 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
@@ -37,6 +40,8 @@ type DensifySettings struct {
 	password     string
 	timeout      int
 	techPlatform string
+	source       string
+	sourceFile   string
 	// cloud.
 	accountName          string
 	accountNumber        string
@@ -53,6 +58,10 @@ type DensifySettings struct {
 	fallbackCPULim string
 	fallbackMemReq string
 	fallbackMemLim string
+	// provider attributes that are unknown during plan.
+	unknown map[string]bool
+	// source of each provider attribute, see setSource.
+	sources map[string]string
 }
 
 // densifyProvider is the provider implementation.
@@ -70,6 +79,8 @@ type densifyProviderModel struct {
 	Password             types.String `tfsdk:"password"`
 	ApiTimeout           types.Int64  `tfsdk:"api_timeout"`
 	TechPlatform         types.String `tfsdk:"tech_platform"`
+	Source               types.String `tfsdk:"source"`
+	SourceFile           types.String `tfsdk:"source_file"`
 	AccountNumber        types.String `tfsdk:"account_number"`
 	AccountName          types.String `tfsdk:"account_name"`
 	SystemName           types.String `tfsdk:"system_name"`
@@ -118,17 +129,25 @@ func (p *densifyProvider) Schema(_ context.Context, _ provider.SchemaRequest, re
 			},
 			"tech_platform": schema.StringAttribute{
 				Optional:    true,
-				Description: "Which Cloud Service Provider (CSP) / technology platform to use for the Densify API. May also be provided via DENSIFY_TECH_PLATFORM environment variable. Accepted values are: aws, azure, gcp, k8s, kubernetes.",
+				Description: "Default Cloud Service Provider (CSP) / technology platform of the cloud data sources, which can override it with their own platform argument. The container data sources always use kubernetes. May also be provided via DENSIFY_TECH_PLATFORM environment variable. Accepted values are: aws, azure, gcp, k8s, kubernetes.",
+			},
+			"source": schema.StringAttribute{
+				Optional:    true,
+				Description: "Where the data sources read the recommendations from. Accepted values are: api (default) to query the Densify API, and file to read a recommendations file exported with the export command, for environments that cannot reach Densify. May also be provided via DENSIFY_SOURCE environment variable.",
+			},
+			"source_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path of the JSON or CSV (.csv extension) recommendations file read when source is file. May also be provided via DENSIFY_SOURCE_FILE environment variable.",
 			},
 
 			// cloud parameters.
 			"account_number": schema.StringAttribute{
 				Optional:    true,
-				Description: "The CSP (Cloud Service Provider) account number to check for a recommendation.",
+				Description: "The CSP (Cloud Service Provider) account number to check for a recommendation. Data sources can override it with their own account_number or account_name.",
 			},
 			"account_name": schema.StringAttribute{
 				Optional:    true,
-				Description: "The CSP (Cloud Service Provider) account name to check for a recommendation.",
+				Description: "The CSP (Cloud Service Provider) account name to check for a recommendation. Data sources can override it with their own account_number or account_name.",
 			},
 			"system_name": schema.StringAttribute{
 				Optional:    true,
@@ -187,6 +206,8 @@ func (p *densifyProvider) Schema(_ context.Context, _ provider.SchemaRequest, re
 // Configure prepares a Densify API client for data sources and resources.
 func (p *densifyProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	tflog.Debug(ctx, "Configuring Densify client")
+	ctx, span := startSpan(ctx, "densify.Configure")
+	defer func() { endSpan(span, resp.Diagnostics) }()
 	// Retrieve provider data from configuration
 	var config densifyProviderModel
 	diags := req.Config.Get(ctx, &config)
@@ -195,15 +216,8 @@ func (p *densifyProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
-	// validate all the config parameters for the provider.
-	config.ValidateProviderParameters(resp)
-
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
 	// Default values to environment variables, but override with Terraform configuration value if set.
-	densifysettings := DensifySettings{}
+	densifysettings := DensifySettings{unknown: config.UnknownParameters()}
 	tflog.Debug(ctx, "Loading Densify Settings from Environment Variables")
 	densifysettings.LoadEnvironmentVariablesSettings(config)
 	tflog.Debug(ctx, "Loading Densify Settings from Provider config")
@@ -215,80 +229,23 @@ func (p *densifyProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
-	// Send variables to log.
-	ctx = tflog.SetField(ctx, "densify_instance", densifysettings.instance)
-	ctx = tflog.SetField(ctx, "densify_username", densifysettings.username)
-	ctx = tflog.SetField(ctx, "densify_password", densifysettings.password)
-	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "densify_password")
-	ctx = tflog.SetField(ctx, "densify_api_timeout", densifysettings.timeout)
-	ctx = tflog.SetField(ctx, "densify_tech_platform", densifysettings.techPlatform)
-	ctx = tflog.SetField(ctx, "densify_account_name", densifysettings.accountName)
-	ctx = tflog.SetField(ctx, "densify_account_number", densifysettings.accountNumber)
-	ctx = tflog.SetField(ctx, "densify_system_name", densifysettings.systemName)
-	ctx = tflog.SetField(ctx, "densify_fallback_instance_type", densifysettings.fallbackInstanceType)
-	ctx = tflog.SetField(ctx, "densify_continue_if_error", densifysettings.continueIfError)
-	ctx = tflog.SetField(ctx, "densify_cluster", densifysettings.cluster)
-	ctx = tflog.SetField(ctx, "densify_namespace", densifysettings.namespace)
-	ctx = tflog.SetField(ctx, "densify_controller_type", densifysettings.controllerType)
-	ctx = tflog.SetField(ctx, "densify_pod_name", densifysettings.podName)
-	ctx = tflog.SetField(ctx, "densify_container_name", densifysettings.containerName)
-	ctx = tflog.SetField(ctx, "densify_fallback_cpu_req", densifysettings.fallbackCPUReq)
-	ctx = tflog.SetField(ctx, "densify_fallback_cpu_lim", densifysettings.fallbackCPULim)
-	ctx = tflog.SetField(ctx, "densify_fallback_mem_req", densifysettings.fallbackMemReq)
-	ctx = tflog.SetField(ctx, "densify_fallback_mem_lim", densifysettings.fallbackMemLim)
-	tflog.Debug(ctx, "Creating Densify API client")
-
-	// Create a new Densify client using the configuration values.
-	client, err := densify.NewDensifyClient(&densifysettings.instance, &densifysettings.username, &densifysettings.password, densifysettings.timeout)
-	if err != nil && !densifysettings.continueIfError {
-		resp.Diagnostics.AddError(
-			"Unable to Create Densify API Client",
-			"An unexpected error occurred when creating the Densify API client. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Densify Client Error: "+err.Error(),
-		)
-		return
-	}
-
-	// set configuration for Densify API Client.
-	densifyAPIQuery := densify.DensifyAPIQuery{
+	// Send the effective configuration to log.
+	densifysettings.LogSummary(ctx)
+	recordQuery(ctx, &densify.DensifyAPIQuery{
 		AnalysisTechnology: densifysettings.techPlatform,
-		AccountName:        densifysettings.accountName,
 		AccountNumber:      densifysettings.accountNumber,
-		SystemName:         densifysettings.systemName,
-		FallbackInstance:   densifysettings.fallbackInstanceType,
-		SkipErrors:         densifysettings.continueIfError,
-
-		K8sCluster:        densifysettings.cluster,
-		K8sNamespace:      densifysettings.namespace,
-		K8sControllerType: densifysettings.controllerType,
-		K8sPodName:        densifysettings.podName,
-		K8sContainerName:  densifysettings.containerName,
-
-		FallbackCPURequest: densifysettings.fallbackCPUReq,
-		FallbackCPULimit:   densifysettings.fallbackCPULim,
-		FallbackMemRequest: densifysettings.fallbackMemReq,
-		FallbackMemLimit:   densifysettings.fallbackMemLim,
-	}
-
-	tflog.Debug(ctx, "Validating Densify client query")
-	err = client.ConfigureQuery(&densifyAPIQuery)
-	if err != nil && !densifysettings.continueIfError {
-		resp.Diagnostics.AddError(
-			"Unable to create Densify query",
-			"An unexpected error occurred when creating the Densify API query. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Densify Client Query Error: "+err.Error(),
-		)
-		return
+		AccountName:        densifysettings.accountName,
+		K8sCluster:         densifysettings.cluster,
+	})
+	if len(densifysettings.unknown) > 0 {
+		tflog.Debug(ctx, "Densify provider configuration has unknown values, data sources that use them are deferred")
 	}
-	tflog.Debug(ctx, "Validated Densify client query")
 
-	// Make the Densify client available during DataSource and Resource type Configure methods.
-	resp.DataSourceData = client
-	resp.ResourceData = client
-
-	tflog.Debug(ctx, "Configured Densify client", map[string]any{"success": true})
+	// Make the Densify settings available during DataSource and Resource type Configure methods. The
+	// client itself is created by the first data source read.
+	providerData := &densifyProviderData{settings: densifysettings}
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
 }
 
 // DataSources defines the data sources implemented in the provider.
@@ -297,6 +254,12 @@ func (p *densifyProvider) DataSources(ctx context.Context) []func() datasource.D
 	return []func() datasource.DataSource{
 		NewDensifyDataSourceCloud,
 		NewDensifyDataSourceContainer,
+		NewDensifyDataSourceAzureVM,
+		NewDensifyDataSourceGCPInstance,
+		NewDensifyDataSourceAWSASG,
+		NewDensifyDataSourceDatabase,
+		NewDensifyDataSourceK8sNodeGroup,
+		NewDensifyDataSourceK8sResourcePatch,
 	}
 }
 
@@ -306,96 +269,42 @@ func (p *densifyProvider) Resources(ctx context.Context) []func() resource.Resou
 	return []func() resource.Resource{}
 }
 
-func (config *densifyProviderModel) ValidateProviderParameters(resp *provider.ConfigureResponse) {
-	// If practitioner provided a configuration value for any of the
-	// attributes, it must be a known value.
-	if config.DensifyInstance.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("densify_instance"),
-			"Unknown Densify API Instance",
-			"The provider cannot create the Densify API client as there is an unknown configuration value for the Densify API instance name. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the DENSIFY_INSTANCE environment variable.",
-		)
-	}
-
-	if config.Username.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("username"),
-			"Unknown Densify API Username",
-			"The provider cannot create the Densify API client as there is an unknown configuration value for the Densify API username. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the DENSIFY_USERNAME environment variable.",
-		)
-	}
-
-	if config.Password.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("password"),
-			"Unknown Densify API Password",
-			"The provider cannot create the Densify API client as there is an unknown configuration value for the Densify API password. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the DENSIFY_PASSWORD environment variable.",
-		)
-	}
-
-	if config.TechPlatform.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("tech_platform"),
-			"Unknown Densify API Technology Platform",
-			"The provider cannot create the Densify API client as there is an unknown configuration value for the Densify API Technology Platform. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the DENSIFY_TECH_PLATFORM environment variable.",
-		)
+// UnknownParameters returns the provider attributes whose value is unknown during plan, ex. when they are set
+// from a resource that is not created yet. Data sources using them defer their read until the values are known.
+func (config *densifyProviderModel) UnknownParameters() map[string]bool {
+	unknown := map[string]bool{}
+	for name, value := range config.attributes() {
+		if value.IsUnknown() {
+			unknown[name] = true
+		}
 	}
+	return unknown
+}
 
-	// KUBERNETES/CONTAINERS.
-	if strings.ToLower(config.TechPlatform.ValueString()) == "k8s" || strings.ToLower(config.TechPlatform.ValueString()) == "kubernetes" {
-		if config.K8sCluster.IsUnknown() {
-			resp.Diagnostics.AddAttributeError(
-				path.Root("cluster"),
-				"Unknown Kubernetes Cluster",
-				"The provider cannot create the Densify API client as there is an unknown configuration value for the Kubernetes Cluster. "+
-					"Either target apply the source of the value first, set the value statically in the configuration, or use the DENSIFY_CLUSTER environment variable.",
-			)
-		}
-		if config.K8sNamespace.IsUnknown() {
-			resp.Diagnostics.AddAttributeError(
-				path.Root("namespace"),
-				"Unknown Kubernetes Namespace",
-				"The provider cannot create the Densify API client as there is an unknown configuration value for the Kubernetes Namespace. "+
-					"Either target apply the source of the value first, set the value statically in the configuration, or use the DENSIFY_NAMESPACE environment variable.",
-			)
-		}
-		if config.K8sControllerType.IsUnknown() {
-			resp.Diagnostics.AddAttributeError(
-				path.Root("controller_type"),
-				"Unknown Kubernetes Controller Type",
-				"The provider cannot create the Densify API client as there is an unknown configuration value for the Kubernetes Controller Type. "+
-					"Either target apply the source of the value first, set the value statically in the configuration, or use the DENSIFY_CONTROLLER_TYPE environment variable.",
-			)
-		}
-		if config.K8sPodName.IsUnknown() {
-			resp.Diagnostics.AddAttributeError(
-				path.Root("pod_name"),
-				"Unknown Kubernetes Pod Name",
-				"The provider cannot create the Densify API client as there is an unknown configuration value for the Kubernetes Pod Name. "+
-					"Either target apply the source of the value first, set the value statically in the configuration, or use the DENSIFY_POD_NAME environment variable.",
-			)
-		}
-	} else { // CLOUD.
-		if config.AccountName.IsUnknown() {
-			resp.Diagnostics.AddAttributeError(
-				path.Root("account_name"),
-				"Unknown Densify API Account Name",
-				"The provider cannot create the Densify API client as there is an unknown configuration value for the Densify API Account Name. "+
-					"Either target apply the source of the value first, set the value statically in the configuration, or use the DENSIFY_ACCOUNT_NAME environment variable.",
-			)
-		}
-		if config.SystemName.IsUnknown() {
-			resp.Diagnostics.AddAttributeError(
-				path.Root("system_name"),
-				"Unknown Densify System Name",
-				"The provider cannot create the Densify API client as there is an unknown configuration value for the Densify System Name. "+
-					"Either target apply the source of the value first, set the value statically in the configuration, or use the DENSIFY_SYSTEM_NAME environment variable.",
-			)
-		}
+// attributes returns the value of each provider attribute by name.
+func (config *densifyProviderModel) attributes() map[string]attr.Value {
+	return map[string]attr.Value{
+		"densify_instance":       config.DensifyInstance,
+		"username":               config.Username,
+		"password":               config.Password,
+		"api_timeout":            config.ApiTimeout,
+		"tech_platform":          config.TechPlatform,
+		"source":                 config.Source,
+		"source_file":            config.SourceFile,
+		"account_number":         config.AccountNumber,
+		"account_name":           config.AccountName,
+		"system_name":            config.SystemName,
+		"fallback_instance_type": config.FallbackInstanceType,
+		"continue_if_error":      config.ContinueIfError,
+		"cluster":                config.K8sCluster,
+		"namespace":              config.K8sNamespace,
+		"controller_type":        config.K8sControllerType,
+		"pod_name":               config.K8sPodName,
+		"container_name":         config.K8sContainerName,
+		"fallback_cpu_req":       config.K8sFallbackCPUReq,
+		"fallback_cpu_lim":       config.K8sFallbackCPULim,
+		"fallback_mem_req":       config.K8sFallbackMemReq,
+		"fallback_mem_lim":       config.K8sFallbackMemLim,
 	}
 }
 
@@ -403,11 +312,13 @@ func (config *densifyProviderModel) ValidateProviderParameters(resp *provider.Co
 func (densifysettings *DensifySettings) LoadEnvironmentVariablesSettings(config densifyProviderModel) {
 	// set default timeout (seconds);
 	tout := 45
+	densifysettings.setSource("api_timeout", sourceDefault)
 	// gracefully handle if the timeout is not a valid int.
 	if val, err := strconv.Atoi(os.Getenv("DENSIFY_API_TIMEOUT")); err == nil {
 		// make sure the timeout (seconds) is between 1-300 (5 mins) seconds.
 		if val >= 1 && val <= 300 {
 			tout = val
+			densifysettings.setSource("api_timeout", sourceEnvironment)
 		}
 	}
 	densifysettings.timeout = tout
@@ -415,6 +326,8 @@ func (densifysettings *DensifySettings) LoadEnvironmentVariablesSettings(config
 	densifysettings.username = os.Getenv("DENSIFY_USERNAME")
 	densifysettings.password = os.Getenv("DENSIFY_PASSWORD")
 	densifysettings.techPlatform = os.Getenv("DENSIFY_TECH_PLATFORM")
+	densifysettings.source = os.Getenv("DENSIFY_SOURCE")
+	densifysettings.sourceFile = os.Getenv("DENSIFY_SOURCE_FILE")
 	densifysettings.accountName = os.Getenv("DENSIFY_ACCOUNT_NAME")
 	densifysettings.accountNumber = os.Getenv("DENSIFY_ACCOUNT_NUMBER")
 	densifysettings.systemName = os.Getenv("DENSIFY_SYSTEM_NAME")
@@ -428,172 +341,150 @@ func (densifysettings *DensifySettings) LoadEnvironmentVariablesSettings(config
 	densifysettings.controllerType = os.Getenv("DENSIFY_CONTROLLER_TYPE")
 	densifysettings.podName = os.Getenv("DENSIFY_POD_NAME")
 	densifysettings.containerName = os.Getenv("DENSIFY_CONTAINER_NAME")
+
+	densifysettings.setSource("continue_if_error", sourceDefault)
+	for name, variable := range settingEnvironmentVariables {
+		if name != "api_timeout" && os.Getenv(variable) != "" {
+			densifysettings.setSource(name, sourceEnvironment)
+		}
+	}
 }
 
 // Load Densify settings from Config provided by the user for the Terraform Provider.
 func (densifysettings *DensifySettings) LoadConfigSettings(config densifyProviderModel) {
-	if !config.DensifyInstance.IsNull() {
+	if !config.DensifyInstance.IsNull() && !config.DensifyInstance.IsUnknown() {
 		densifysettings.instance = config.DensifyInstance.ValueString()
 	}
-	if !config.Username.IsNull() {
+	if !config.Username.IsNull() && !config.Username.IsUnknown() {
 		densifysettings.username = config.Username.ValueString()
 	}
-	if !config.Password.IsNull() {
+	if !config.Password.IsNull() && !config.Password.IsUnknown() {
 		densifysettings.password = config.Password.ValueString()
 	}
-	if !config.ApiTimeout.IsNull() {
+	if !config.ApiTimeout.IsNull() && !config.ApiTimeout.IsUnknown() {
 		densifysettings.timeout = int(config.ApiTimeout.ValueInt64())
 	}
-	if !config.TechPlatform.IsNull() {
+	if !config.TechPlatform.IsNull() && !config.TechPlatform.IsUnknown() {
 		densifysettings.techPlatform = config.TechPlatform.ValueString()
 	}
-	if !config.AccountNumber.IsNull() {
+	if !config.Source.IsNull() && !config.Source.IsUnknown() {
+		densifysettings.source = config.Source.ValueString()
+	}
+	if !config.SourceFile.IsNull() && !config.SourceFile.IsUnknown() {
+		densifysettings.sourceFile = config.SourceFile.ValueString()
+	}
+	if !config.AccountNumber.IsNull() && !config.AccountNumber.IsUnknown() {
 		densifysettings.accountNumber = config.AccountNumber.ValueString()
 	}
-	if !config.AccountName.IsNull() {
+	if !config.AccountName.IsNull() && !config.AccountName.IsUnknown() {
 		densifysettings.accountName = config.AccountName.ValueString()
 	}
-	if !config.SystemName.IsNull() {
+	if !config.SystemName.IsNull() && !config.SystemName.IsUnknown() {
 		densifysettings.systemName = config.SystemName.ValueString()
 	}
-	if !config.FallbackInstanceType.IsNull() {
+	if !config.FallbackInstanceType.IsNull() && !config.FallbackInstanceType.IsUnknown() {
 		densifysettings.fallbackInstanceType = config.FallbackInstanceType.ValueString()
 	}
-	if !config.ContinueIfError.IsNull() {
+	if !config.ContinueIfError.IsNull() && !config.ContinueIfError.IsUnknown() {
 		densifysettings.continueIfError = config.ContinueIfError.ValueBool()
 	}
 
-	if !config.K8sCluster.IsNull() {
+	if !config.K8sCluster.IsNull() && !config.K8sCluster.IsUnknown() {
 		densifysettings.cluster = config.K8sCluster.ValueString()
 	}
-	if !config.K8sNamespace.IsNull() {
+	if !config.K8sNamespace.IsNull() && !config.K8sNamespace.IsUnknown() {
 		densifysettings.namespace = config.K8sNamespace.ValueString()
 	}
-	if !config.K8sControllerType.IsNull() {
+	if !config.K8sControllerType.IsNull() && !config.K8sControllerType.IsUnknown() {
 		densifysettings.controllerType = config.K8sControllerType.ValueString()
 	}
-	if !config.K8sPodName.IsNull() {
+	if !config.K8sPodName.IsNull() && !config.K8sPodName.IsUnknown() {
 		densifysettings.podName = config.K8sPodName.ValueString()
 	}
-	if !config.K8sContainerName.IsNull() {
+	if !config.K8sContainerName.IsNull() && !config.K8sContainerName.IsUnknown() {
 		densifysettings.containerName = config.K8sContainerName.ValueString()
 	}
 
-	if !config.K8sFallbackCPUReq.IsNull() {
+	if !config.K8sFallbackCPUReq.IsNull() && !config.K8sFallbackCPUReq.IsUnknown() {
 		densifysettings.fallbackCPUReq = config.K8sFallbackCPUReq.ValueString()
 	}
-	if !config.K8sFallbackCPULim.IsNull() {
+	if !config.K8sFallbackCPULim.IsNull() && !config.K8sFallbackCPULim.IsUnknown() {
 		densifysettings.fallbackCPULim = config.K8sFallbackCPULim.ValueString()
 	}
-	if !config.K8sFallbackMemReq.IsNull() {
+	if !config.K8sFallbackMemReq.IsNull() && !config.K8sFallbackMemReq.IsUnknown() {
 		densifysettings.fallbackMemReq = config.K8sFallbackMemReq.ValueString()
 	}
-	if !config.K8sFallbackMemLim.IsNull() {
+	if !config.K8sFallbackMemLim.IsNull() && !config.K8sFallbackMemLim.IsUnknown() {
 		densifysettings.fallbackMemLim = config.K8sFallbackMemLim.ValueString()
 	}
+
+	for name, value := range config.attributes() {
+		if !value.IsNull() && !value.IsUnknown() {
+			densifysettings.setSource(name, sourceConfig)
+		}
+	}
 }
 
-// Load Densify settings from Config provided by the user for the Terraform Provider.
+// Validate that the Densify settings needed to create the recommendation source (the API client or the
+// recommendations file) are set. The technology platform, accounts and Kubernetes settings are checked by the
+// data sources that use them, and values unknown during plan once they are known.
 func (densifysettings *DensifySettings) ValidateSettings(resp *provider.ConfigureResponse) {
 	// If any of the expected configurations are missing, return errors with provider-specific guidance.
 
-	if densifysettings.instance == "" {
+	densifysettings.source = strings.ToLower(densifysettings.source)
+	if densifysettings.source == "" {
+		densifysettings.source = sourceTypeAPI
+	}
+	if !densifysettings.unknown["source"] && !slices.Contains(sourceTypes, densifysettings.source) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("source"),
+			"Invalid Densify Recommendation Source",
+			fmt.Sprintf("The recommendation source %q is not supported: %s. Accepted values are: %s.",
+				densifysettings.source, densifysettings.describe("source"), strings.Join(sourceTypes, ", ")),
+		)
+	}
+	// the credentials are not used when the recommendations are read from a file.
+	fromFile := densifysettings.source == sourceTypeFile
+	if fromFile && densifysettings.sourceFile == "" && !densifysettings.unknown["source_file"] {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("source_file"),
+			"Missing Densify Recommendations File",
+			"The provider cannot read the recommendations from a file as there is a missing or empty value for the file path: "+
+				densifysettings.describe("source_file")+". "+
+				"Set the source_file value in the configuration or use the DENSIFY_SOURCE_FILE environment variable.",
+		)
+	}
+
+	if densifysettings.instance == "" && !densifysettings.unknown["densify_instance"] && !fromFile {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("densify_instance"),
 			"Missing Densify API Instance Name",
-			"The provider cannot create the Densify API client as there is a missing or empty value for the Densify API Instance Name. "+
+			"The provider cannot create the Densify API client as there is a missing or empty value for the Densify API Instance Name: "+
+				densifysettings.describe("densify_instance")+". "+
 				"Set the instance value in the configuration or use the DENSIFY_INSTANCE environment variable. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
-	if densifysettings.username == "" {
+	if densifysettings.username == "" && !densifysettings.unknown["username"] && !fromFile {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("username"),
 			"Missing Densify API Username",
-			"The provider cannot create the Densify API client as there is a missing or empty value for the Densify API username. "+
+			"The provider cannot create the Densify API client as there is a missing or empty value for the Densify API username: "+
+				densifysettings.describe("username")+". "+
 				"Set the username value in the configuration or use the DENSIFY_USERNAME environment variable. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
-	if densifysettings.password == "" {
+	if densifysettings.password == "" && !densifysettings.unknown["password"] && !fromFile {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("password"),
 			"Missing Densify API Password",
-			"The provider cannot create the Densify API client as there is a missing or empty value for the Densify API password. "+
+			"The provider cannot create the Densify API client as there is a missing or empty value for the Densify API password: "+
+				densifysettings.describe("password")+". "+
 				"Set the password value in the configuration or use the DENSIFY_PASSWORD environment variable. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
-
-	if densifysettings.techPlatform == "" {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("tech_platform"),
-			"Missing Densify API Technology Platform",
-			"The provider cannot create the Densify API client as there is a missing or empty value for the Densify API Technology Platform. "+
-				"Set the tech_platform value in the configuration or use the DENSIFY_TECH_PLATFORM environment variable. "+
-				"If either is already set, ensure the value is not empty.",
-		)
-	}
-
-	// KUBERNETES/CONTAINERS.
-	if strings.ToLower(densifysettings.techPlatform) == "k8s" || strings.ToLower(densifysettings.techPlatform) == "kubernetes" {
-		if densifysettings.cluster == "" {
-			resp.Diagnostics.AddAttributeError(
-				path.Root("cluster"),
-				"Missing Kubernetes Cluster Name",
-				"The provider cannot create the Densify API client as there is a missing or empty value for the Cluster Name. "+
-					"Set the cluster value in the configuration or use the DENSIFY_CLUSTER environment variable. "+
-					"If either is already set, ensure the value is not empty.",
-			)
-		}
-		if densifysettings.namespace == "" {
-			resp.Diagnostics.AddAttributeError(
-				path.Root("namespace"),
-				"Missing Kubernetes Namespace",
-				"The provider cannot create the Densify API client as there is a missing or empty value for the Namespace. "+
-					"Set the namespace value in the configuration or use the DENSIFY_NAMESPACE environment variable. "+
-					"If either is already set, ensure the value is not empty.",
-			)
-		}
-		if densifysettings.controllerType == "" {
-			resp.Diagnostics.AddAttributeError(
-				path.Root("controller_type"),
-				"Missing Kubernetes Cluster Name",
-				"The provider cannot create the Densify API client as there is a missing or empty value for the Controller Type. "+
-					"Set the controller_type value in the configuration. "+
-					"If it is already set, ensure the value is not empty.",
-			)
-		}
-		if densifysettings.podName == "" {
-			resp.Diagnostics.AddAttributeError(
-				path.Root("pod_name"),
-				"Missing Kubernetes Pod Name",
-				"The provider cannot create the Densify API client as there is a missing or empty value for the Pod Name. "+
-					"Set the pod_name value in the configuration. "+
-					"If it is already set, ensure the value is not empty.",
-			)
-		}
-
-	} else {
-		if densifysettings.accountName == "" && densifysettings.accountNumber == "" {
-			resp.Diagnostics.AddAttributeError(
-				path.Root("account_number"),
-				"Missing Densify API Account Name/Number",
-				"The provider cannot create the Densify API client as there is a missing or empty value for the Densify API Account Number or Account Name. "+
-					"Set the account_number value in the configuration or use the DENSIFY_ACCOUNT_NUMBER environment variable. "+
-					"If either is already set, ensure the value is not empty.",
-			)
-		}
-		if densifysettings.systemName == "" {
-			resp.Diagnostics.AddAttributeError(
-				path.Root("system_name"),
-				"Missing Densify System Name",
-				"The provider cannot create the Densify API client as there is a missing or empty value for the Densify System Name. "+
-					"Set the system_name value in the configuration or use the DENSIFY_SYSTEM_NAME environment variable. "+
-					"If either is already set, ensure the value is not empty.",
-			)
-		}
-	}
 }
@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+// Provider attributes used by the data sources. A data source read is deferred while any of them is unknown.
+var (
+	clientAttributes = []string{"densify_instance", "username", "password", "api_timeout", "continue_if_error", "source", "source_file"}
+	cloudAttributes  = append([]string{"tech_platform", "account_number", "account_name", "system_name", "fallback_instance_type"}, clientAttributes...)
+	k8sAttributes    = append([]string{"cluster", "namespace", "controller_type", "pod_name", "container_name",
+		"fallback_cpu_req", "fallback_cpu_lim", "fallback_mem_req", "fallback_mem_lim"}, clientAttributes...)
+)
+
+// densifyProviderData is passed by the provider to its data sources. The recommendation source (the Densify
+// API client) is only created by the first data source read, so that provider attributes which are unknown
+// during plan (ex. a cluster name from a resource that is not created yet) do not fail the provider configuration.
+type densifyProviderData struct {
+	settings DensifySettings
+	accounts accountCache
+
+	once   sync.Once
+	source RecommendationSource
+	err    error
+}
+
+// readSource returns the recommendation source for a data source read that depends on the given provider
+// attributes. It returns false when the read must stop: the client could not be created (the error is
+// added unless continue_if_error is set), or some of the attributes are unknown until apply. In that case the
+// read is deferred with a warning, so Terraform plans the data source attributes as known after apply, when the
+// client supports deferred actions. Otherwise an error names the unknown attributes, since leaving the attributes
+// null would plan null values (ex. a null instance type) for the resources that use them.
+func (p *densifyProviderData) readSource(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse, attributes []string) (RecommendationSource, bool) {
+	unknown := []string{}
+	for _, name := range attributes {
+		if p.settings.unknown[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		tflog.Debug(ctx, "Deferring Densify data source read until the provider configuration is known", map[string]any{
+			"unknown":          unknown,
+			"deferral_allowed": req.ClientCapabilities.DeferralAllowed,
+		})
+		if req.ClientCapabilities.DeferralAllowed {
+			resp.Deferred = &datasource.Deferred{Reason: datasource.DeferredReasonProviderConfigUnknown}
+			resp.Diagnostics.AddWarning(
+				"Densify Provider Configuration Unknown",
+				fmt.Sprintf("The provider attributes %s are not known until apply, so the recommendation is read once they are known "+
+					"and the data source attributes are known after apply.", strings.Join(unknown, ", ")),
+			)
+			return nil, false
+		}
+		resp.Diagnostics.AddError(
+			"Densify Provider Configuration Unknown",
+			"The provider cannot read the Densify recommendation as some of its attributes are not known until apply. "+
+				"Set the values statically or with their environment variables, or run Terraform with deferred actions enabled "+
+				"to plan the data source attributes as known after apply."+
+				p.settings.explain(unknown...),
+		)
+		return nil, false
+	}
+
+	p.once.Do(func() {
+		p.source, p.err = p.settings.newSource(ctx)
+	})
+	if p.err != nil {
+		if p.settings.source == sourceTypeFile {
+			resp.Diagnostics.AddError(
+				"Unable to Read Densify Recommendations File",
+				"Densify Recommendations File Error: "+p.err.Error()+p.settings.explain("source", "source_file"),
+			)
+		} else if !p.settings.continueIfError {
+			resp.Diagnostics.AddError(
+				"Unable to Create Densify API Client",
+				"An unexpected error occurred when creating the Densify API client. "+
+					"If the error is not clear, please contact the provider developers.\n\n"+
+					"Densify Client Error: "+p.err.Error()+
+					p.settings.explain("densify_instance", "username", "password", "api_timeout", "tech_platform"),
+			)
+		}
+		return nil, false
+	}
+	return p.source.WithContext(ctx), true
+}
+
+// newSource creates the recommendation source of the settings: the Densify API client, or the recommendations
+// file when source is file. Its default query is configured from the settings.
+func (densifysettings *DensifySettings) newSource(ctx context.Context) (RecommendationSource, error) {
+	// set configuration for Densify API Client.
+	densifyAPIQuery := densify.DensifyAPIQuery{
+		AnalysisTechnology: densifysettings.techPlatform,
+		AccountName:        densifysettings.accountName,
+		AccountNumber:      densifysettings.accountNumber,
+		SystemName:         densifysettings.systemName,
+		FallbackInstance:   densifysettings.fallbackInstanceType,
+		SkipErrors:         densifysettings.continueIfError,
+
+		K8sCluster:        densifysettings.cluster,
+		K8sNamespace:      densifysettings.namespace,
+		K8sControllerType: densifysettings.controllerType,
+		K8sPodName:        densifysettings.podName,
+		K8sContainerName:  densifysettings.containerName,
+
+		FallbackCPURequest: densifysettings.fallbackCPUReq,
+		FallbackCPULimit:   densifysettings.fallbackCPULim,
+		FallbackMemRequest: densifysettings.fallbackMemReq,
+		FallbackMemLimit:   densifysettings.fallbackMemLim,
+	}
+
+	if densifysettings.source == sourceTypeFile {
+		tflog.Debug(ctx, "Reading Densify recommendations file", map[string]any{"path": densifysettings.sourceFile})
+		return newFileSource(densifysettings.sourceFile, &densifyAPIQuery)
+	}
+
+	tflog.Debug(ctx, "Creating Densify API client")
+	client, err := densify.NewDensifyClient(&densifysettings.instance, &densifysettings.username, &densifysettings.password, densifysettings.timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	tflog.Debug(ctx, "Validating Densify client query")
+	if densifysettings.techPlatform == "" {
+		// no provider level platform: each data source selects (and validates) its own query.
+		client.Query = &densifyAPIQuery
+	} else if err := client.ConfigureQuery(&densifyAPIQuery); err != nil {
+		return nil, fmt.Errorf("unable to create Densify query: %w", err)
+	}
+	tflog.Debug(ctx, "Configured Densify client", map[string]any{"success": true})
+	return &apiSource{client: client, username: densifysettings.username}, nil
+}
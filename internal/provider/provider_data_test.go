@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+)
+
+func TestReadSourceUnknownConfiguration(t *testing.T) {
+	data := &densifyProviderData{settings: DensifySettings{unknown: map[string]bool{"cluster": true}}}
+	tests := []struct {
+		name            string
+		deferralAllowed bool
+		wantDeferred    bool
+	}{
+		{"deferral allowed", true, true},
+		{"deferral not allowed", false, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := datasource.ReadRequest{ClientCapabilities: datasource.ReadClientCapabilities{DeferralAllowed: test.deferralAllowed}}
+			resp := &datasource.ReadResponse{}
+			source, ok := data.readSource(context.Background(), req, resp, k8sAttributes)
+			if ok || source != nil {
+				t.Fatalf("readSource() = %v, %t, want no source", source, ok)
+			}
+			if got := resp.Deferred != nil; got != test.wantDeferred {
+				t.Errorf("readSource() deferred = %v, want deferred %t", resp.Deferred, test.wantDeferred)
+			}
+			if resp.Deferred != nil && resp.Deferred.Reason != datasource.DeferredReasonProviderConfigUnknown {
+				t.Errorf("readSource() deferred reason = %s, want %s", resp.Deferred.Reason, datasource.DeferredReasonProviderConfigUnknown)
+			}
+			if test.wantDeferred && (resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1) {
+				t.Errorf("readSource() diagnostics = %v, want one warning", resp.Diagnostics)
+			}
+			if !test.wantDeferred && (resp.Diagnostics.ErrorsCount() != 1 || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "cluster is unknown until apply")) {
+				t.Errorf("readSource() diagnostics = %v, want an error naming the unknown cluster", resp.Diagnostics)
+			}
+		})
+	}
+
+	// cloud data sources do not depend on the cluster, so they are not deferred.
+	data = &densifyProviderData{settings: DensifySettings{
+		unknown:    map[string]bool{"cluster": true},
+		source:     sourceTypeFile,
+		sourceFile: t.TempDir() + "/missing.json",
+	}}
+	resp := &datasource.ReadResponse{}
+	data.readSource(context.Background(), datasource.ReadRequest{ClientCapabilities: datasource.ReadClientCapabilities{DeferralAllowed: true}}, resp, cloudAttributes)
+	if resp.Deferred != nil {
+		t.Errorf("readSource() deferred = %v, want a cloud read not to be deferred", resp.Deferred)
+	}
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("readSource() diagnostics = %v, want the missing file error", resp.Diagnostics)
+	}
+}
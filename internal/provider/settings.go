@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Sources of the Densify settings.
+const (
+	sourceConfig      = "config"
+	sourceEnvironment = "env"
+	sourceDefault     = "default"
+)
+
+// settingEnvironmentVariables maps the provider attributes to the environment variable they may be set with.
+var settingEnvironmentVariables = map[string]string{
+	"densify_instance":       "DENSIFY_INSTANCE",
+	"username":               "DENSIFY_USERNAME",
+	"password":               "DENSIFY_PASSWORD",
+	"api_timeout":            "DENSIFY_API_TIMEOUT",
+	"tech_platform":          "DENSIFY_TECH_PLATFORM",
+	"source":                 "DENSIFY_SOURCE",
+	"source_file":            "DENSIFY_SOURCE_FILE",
+	"account_name":           "DENSIFY_ACCOUNT_NAME",
+	"account_number":         "DENSIFY_ACCOUNT_NUMBER",
+	"system_name":            "DENSIFY_SYSTEM_NAME",
+	"fallback_instance_type": "DENSIFY_FALLBACK_INSTANCE_TYPE",
+	"continue_if_error":      "DENSIFY_CONTINUE_IF_ERROR",
+	"cluster":                "DENSIFY_CLUSTER",
+	"namespace":              "DENSIFY_NAMESPACE",
+	"controller_type":        "DENSIFY_CONTROLLER_TYPE",
+	"pod_name":               "DENSIFY_POD_NAME",
+	"container_name":         "DENSIFY_CONTAINER_NAME",
+}
+
+// sensitiveSettings lists the provider attributes whose value is never logged or shown in diagnostics.
+var sensitiveSettings = map[string]bool{"password": true}
+
+// setSource records where the value of a provider attribute comes from.
+func (densifysettings *DensifySettings) setSource(name string, source string) {
+	if densifysettings.sources == nil {
+		densifysettings.sources = map[string]string{}
+	}
+	densifysettings.sources[name] = source
+}
+
+// values returns the effective value of each provider attribute.
+func (densifysettings *DensifySettings) values() map[string]string {
+	return map[string]string{
+		"densify_instance":       densifysettings.instance,
+		"username":               densifysettings.username,
+		"password":               densifysettings.password,
+		"api_timeout":            strconv.Itoa(densifysettings.timeout),
+		"tech_platform":          densifysettings.techPlatform,
+		"source":                 densifysettings.source,
+		"source_file":            densifysettings.sourceFile,
+		"account_name":           densifysettings.accountName,
+		"account_number":         densifysettings.accountNumber,
+		"system_name":            densifysettings.systemName,
+		"fallback_instance_type": densifysettings.fallbackInstanceType,
+		"continue_if_error":      strconv.FormatBool(densifysettings.continueIfError),
+		"cluster":                densifysettings.cluster,
+		"namespace":              densifysettings.namespace,
+		"controller_type":        densifysettings.controllerType,
+		"pod_name":               densifysettings.podName,
+		"container_name":         densifysettings.containerName,
+		"fallback_cpu_req":       densifysettings.fallbackCPUReq,
+		"fallback_cpu_lim":       densifysettings.fallbackCPULim,
+		"fallback_mem_req":       densifysettings.fallbackMemReq,
+		"fallback_mem_lim":       densifysettings.fallbackMemLim,
+	}
+}
+
+// sourceName describes the source of a provider attribute. Ex. "DENSIFY_CLUSTER environment variable".
+func (densifysettings *DensifySettings) sourceName(name string) string {
+	if densifysettings.unknown[name] {
+		return "unknown until apply"
+	}
+	switch densifysettings.sources[name] {
+	case sourceConfig:
+		return "provider configuration"
+	case sourceEnvironment:
+		return settingEnvironmentVariables[name] + " environment variable"
+	case sourceDefault:
+		return "default"
+	}
+	return "not set"
+}
+
+// describe says which source supplied the value of a provider attribute, or which ones failed to supply it.
+// Ex. `cluster "prod" is set by the DENSIFY_CLUSTER environment variable`.
+func (densifysettings *DensifySettings) describe(name string) string {
+	value := fmt.Sprintf(" %q", densifysettings.values()[name])
+	if sensitiveSettings[name] {
+		value = ""
+	}
+	if densifysettings.unknown[name] {
+		return name + " is unknown until apply in the provider configuration"
+	}
+	switch densifysettings.sources[name] {
+	case sourceConfig:
+		return fmt.Sprintf("%s%s is set in the provider configuration", name, value)
+	case sourceEnvironment:
+		return fmt.Sprintf("%s%s is set by the %s environment variable", name, value, settingEnvironmentVariables[name])
+	case sourceDefault:
+		return fmt.Sprintf("%s%s is the default value", name, value)
+	}
+	if variable, ok := settingEnvironmentVariables[name]; ok {
+		return fmt.Sprintf("%s is not set in the provider configuration or the %s environment variable", name, variable)
+	}
+	return name + " is not set in the provider configuration"
+}
+
+// explain lists the sources of the given provider attributes, to be appended to a diagnostic detail.
+func (densifysettings *DensifySettings) explain(names ...string) string {
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, "- "+densifysettings.describe(name))
+	}
+	return "\n\nProvider settings:\n" + strings.Join(lines, "\n")
+}
+
+// LogSummary logs the effective configuration and the source of each value at debug level. Secrets are masked.
+func (densifysettings *DensifySettings) LogSummary(ctx context.Context) {
+	values := densifysettings.values()
+	fields := make(map[string]any, len(values))
+	for name, value := range values {
+		if sensitiveSettings[name] && value != "" {
+			value = "***"
+		}
+		fields["densify_"+strings.TrimPrefix(name, "densify_")] = fmt.Sprintf("%q (%s)", value, densifysettings.sourceName(name))
+	}
+	tflog.Debug(ctx, "Densify provider configuration", fields)
+}
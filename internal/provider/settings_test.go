@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+// loadTestSettings loads the settings like the provider does: the DENSIFY_* environment variables, then the
+// provider configuration.
+func loadTestSettings(t *testing.T, env map[string]string, config densifyProviderModel) *DensifySettings {
+	t.Helper()
+	for _, variable := range settingEnvironmentVariables {
+		t.Setenv(variable, env[variable])
+	}
+	densifysettings := &DensifySettings{}
+	densifysettings.LoadEnvironmentVariablesSettings(config)
+	densifysettings.LoadConfigSettings(config)
+	return densifysettings
+}
+
+func TestSettingsDescribe(t *testing.T) {
+	densifysettings := loadTestSettings(t,
+		map[string]string{"DENSIFY_INSTANCE": "https://env.densify.com", "DENSIFY_PASSWORD": "env-secret", "DENSIFY_CLUSTER": "prod"},
+		densifyProviderModel{Username: types.StringValue("svc"), Password: types.StringValue("config-secret"), K8sCluster: types.StringValue("staging")},
+	)
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"densify_instance", `densify_instance "https://env.densify.com" is set by the DENSIFY_INSTANCE environment variable`},
+		{"username", `username "svc" is set in the provider configuration`},
+		{"password", "password is set in the provider configuration"},
+		{"cluster", `cluster "staging" is set in the provider configuration`},
+		{"continue_if_error", `continue_if_error "false" is the default value`},
+		{"api_timeout", `api_timeout "45" is the default value`},
+		{"namespace", "namespace is not set in the provider configuration or the DENSIFY_NAMESPACE environment variable"},
+		{"fallback_cpu_req", "fallback_cpu_req is not set in the provider configuration"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := densifysettings.describe(test.name); got != test.want {
+				t.Errorf("describe(%q) = %q, want %q", test.name, got, test.want)
+			}
+		})
+	}
+
+	explained := densifysettings.explain("densify_instance", "username", "password")
+	for _, secret := range []string{"config-secret", "env-secret"} {
+		if strings.Contains(explained, secret) {
+			t.Errorf("explain() = %q, shows the password", explained)
+		}
+	}
+}
+
+func TestSettingsDescribePasswordFromEnvironment(t *testing.T) {
+	densifysettings := loadTestSettings(t, map[string]string{"DENSIFY_PASSWORD": "env-secret"}, densifyProviderModel{})
+	want := "password is set by the DENSIFY_PASSWORD environment variable"
+	if got := densifysettings.describe("password"); got != want {
+		t.Errorf("describe(password) = %q, want %q", got, want)
+	}
+}
+
+func TestSettingsLogSummary(t *testing.T) {
+	densifysettings := loadTestSettings(t,
+		map[string]string{"DENSIFY_INSTANCE": "https://env.densify.com", "DENSIFY_PASSWORD": "env-secret"},
+		densifyProviderModel{Password: types.StringValue("config-secret")},
+	)
+	var output bytes.Buffer
+	densifysettings.LogSummary(tflogtest.RootLogger(context.Background(), &output))
+	if strings.Contains(output.String(), "secret") {
+		t.Fatalf("LogSummary() logged the password: %s", output.String())
+	}
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("LogSummary() logged %d entries, want 1", len(entries))
+	}
+	tests := map[string]string{
+		"densify_password":          `"***" (provider configuration)`,
+		"densify_instance":          `"https://env.densify.com" (DENSIFY_INSTANCE environment variable)`,
+		"densify_continue_if_error": `"false" (default)`,
+		"densify_namespace":         `"" (not set)`,
+	}
+	for field, want := range tests {
+		if got := entries[0][field]; got != want {
+			t.Errorf("%s = %v, want %q", field, got, want)
+		}
+	}
+}
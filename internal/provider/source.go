@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/joelpereira/densify-api-client-go"
+)
+
+// RecommendationSource is the backend the data sources read recommendations from. The Densify API client is
+// the default one; mocks, caches or exported files can be plugged in by implementing the same lookups.
+type RecommendationSource interface {
+	// Name identifies the backend and the user it is read as, ex. the user and URL of the Densify instance.
+	// Accounts are cached per name.
+	Name() string
+	// Query returns the lookup settings of the source. Changes to the returned query apply to the source.
+	Query() *densify.DensifyAPIQuery
+	// WithQuery returns a copy of the source with its query adjusted by configure. Data sources use it to
+	// look up other systems than the provider level one without changing the shared source.
+	WithQuery(configure func(query *densify.DensifyAPIQuery)) (RecommendationSource, error)
+	// WithContext returns a copy of the source whose lookups are logged and traced with ctx.
+	WithContext(ctx context.Context) RecommendationSource
+
+	// LookupAccount returns the account number (or Kubernetes cluster) of the query. The other lookups of the
+	// source rely on it being called first.
+	LookupAccount() (string, error)
+	// WithResolvedAccount returns a copy of the source with the account state of resolved, a source of the same
+	// backend and account on which LookupAccount succeeded, so that LookupAccount need not be called again.
+	WithResolvedAccount(resolved RecommendationSource) RecommendationSource
+	// Recommendation returns the recommendation of the query system (or controller), or nil if there is none.
+	Recommendation() (*densify.DensifyRecommendation, error)
+	// Recommendations returns all the recommendations of the query account (or cluster and namespace).
+	Recommendations() ([]densify.DensifyRecommendation, error)
+	// ApprovedType returns the instance type approved for a recommendation: the recommended type once approved,
+	// otherwise the fallback or current type.
+	ApprovedType(reco *densify.DensifyRecommendation) string
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ RecommendationSource = &apiSource{}
+
+// apiSource reads the recommendations from the Densify API.
+type apiSource struct {
+	client   *densify.DensifyClient
+	username string
+}
+
+// Name returns the user and URL of the Densify instance.
+func (s *apiSource) Name() string {
+	return s.username + "@" + s.client.BaseURL
+}
+
+// Query returns the query of the Densify client.
+func (s *apiSource) Query() *densify.DensifyAPIQuery {
+	return s.client.Query
+}
+
+// WithQuery returns a copy of the Densify client with its query adjusted by configure.
+func (s *apiSource) WithQuery(configure func(query *densify.DensifyAPIQuery)) (RecommendationSource, error) {
+	query := densify.DensifyAPIQuery{}
+	if s.client.Query != nil {
+		query = *s.client.Query
+	}
+	configure(&query)
+
+	c := *s.client
+	if err := c.ConfigureQuery(&query); err != nil {
+		return nil, err
+	}
+	return &apiSource{client: &c, username: s.username}, nil
+}
+
+// WithContext returns a copy of the Densify client whose API calls are logged and traced with ctx, since the
+// client does not pass a context to its requests.
+func (s *apiSource) WithContext(ctx context.Context) RecommendationSource {
+	if s.client.HTTPClient == nil {
+		return s
+	}
+	httpClient := *s.client.HTTPClient
+	httpClient.Transport = newTracingTransport(ctx, s.client.HTTPClient.Transport)
+	c := *s.client
+	c.HTTPClient = &httpClient
+	return &apiSource{client: &c, username: s.username}
+}
+
+// LookupAccount calls GetAccountOrCluster.
+func (s *apiSource) LookupAccount() (string, error) {
+	return s.client.GetAccountOrCluster()
+}
+
+// WithResolvedAccount returns a copy of the resolved Densify client with the query and HTTP client of this one,
+// since GetAccountOrCluster keeps the account it resolved in the client. The resolved client must be of the
+// same instance and user, so that no credentials or token of another user are reused.
+func (s *apiSource) WithResolvedAccount(resolved RecommendationSource) RecommendationSource {
+	r, ok := resolved.(*apiSource)
+	if !ok || r.Name() != s.Name() {
+		return s
+	}
+	c := *r.client
+	c.Query = s.client.Query
+	c.HTTPClient = s.client.HTTPClient
+	return &apiSource{client: &c, username: s.username}
+}
+
+// Recommendation calls GetDensifyRecommendation.
+func (s *apiSource) Recommendation() (*densify.DensifyRecommendation, error) {
+	return s.client.GetDensifyRecommendation()
+}
+
+// Recommendations calls GetDensifyRecommendations.
+func (s *apiSource) Recommendations() ([]densify.DensifyRecommendation, error) {
+	return s.client.GetDensifyRecommendations()
+}
+
+// ApprovedType returns the approved type computed by the Densify client.
+func (s *apiSource) ApprovedType(reco *densify.DensifyRecommendation) string {
+	return reco.ApprovedType
+}
@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Accepted values for the on_stale argument.
+const (
+	onStaleFallback = "fallback"
+	onStaleWarn     = "warn"
+	onStaleError    = "error"
+)
+
+// stalenessPolicy decides what to do with recommendations that Densify has not analyzed recently.
+type stalenessPolicy struct {
+	maxAge  time.Duration
+	onStale string
+}
+
+// newStalenessPolicy builds a staleness policy from the max_recommendation_age and on_stale arguments.
+// A nil policy is returned when max_recommendation_age is not set.
+func newStalenessPolicy(maxAge types.String, onStale types.String) (*stalenessPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if maxAge.IsNull() || maxAge.IsUnknown() {
+		return nil, diags
+	}
+
+	age, err := parseRecommendationAge(maxAge.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("max_recommendation_age"),
+			"Invalid Maximum Recommendation Age",
+			err.Error(),
+		)
+		return nil, diags
+	}
+
+	policy := &stalenessPolicy{maxAge: age, onStale: onStaleFallback}
+	if !onStale.IsNull() && !onStale.IsUnknown() {
+		policy.onStale = strings.ToLower(onStale.ValueString())
+	}
+	switch policy.onStale {
+	case onStaleFallback, onStaleWarn, onStaleError:
+	default:
+		diags.AddAttributeError(
+			path.Root("on_stale"),
+			"Invalid On Stale Action",
+			fmt.Sprintf("Unknown on_stale value %q. Accepted values are: fallback, warn, error.", onStale.ValueString()),
+		)
+		return nil, diags
+	}
+	return policy, diags
+}
+
+// parseRecommendationAge parses a Go duration (ex. 720h) or a number of days (ex. 30d).
+func parseRecommendationAge(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("max_recommendation_age %q is not a valid number of days. Ex. 30d", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age <= 0 {
+		return 0, fmt.Errorf("max_recommendation_age %q is not a valid duration. Ex. 30d, 720h", value)
+	}
+	return age, nil
+}
+
+// Apply checks the last-seen date (milliseconds since epoch) of a recommendation against the policy. It is the
+// date reported as last_analyzed, since the client has no analysis run date. A recommendation without a last-seen
+// date is stale, since Densify cannot tell when it was last analyzed.
+// Warnings or errors are added to diags, and true is returned when the fallback values should be used instead.
+func (policy *stalenessPolicy) Apply(diags *diag.Diagnostics, epochMillis int64, now time.Time) bool {
+	if policy == nil {
+		return false
+	}
+	var detail string
+	if epochMillis <= 0 {
+		detail = fmt.Sprintf("The recommendation has no last-seen date, so it cannot be checked against max_recommendation_age (%s). "+
+			"Check that analysis is still running for this account.", policy.maxAge)
+	} else {
+		analyzed := time.UnixMilli(epochMillis)
+		if now.Sub(analyzed) <= policy.maxAge {
+			return false
+		}
+		detail = fmt.Sprintf("The recommendation was last seen by the Densify analysis at %s, which is older than max_recommendation_age (%s). "+
+			"Check that analysis is still running for this account.", analyzed.UTC().Format(time.RFC3339), policy.maxAge)
+	}
+
+	switch policy.onStale {
+	case onStaleError:
+		diags.AddError("Stale Densify Recommendation", detail)
+	case onStaleWarn:
+		diags.AddWarning("Stale Densify Recommendation", detail)
+	default:
+		diags.AddWarning("Stale Densify Recommendation", detail+" The fallback values are used instead.")
+		return true
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func TestParseRecommendationAge(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{" 7d ", 7 * 24 * time.Hour, false},
+		{"720h", 720 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"0d", 0, true},
+		{"-1h", 0, true},
+		{"xd", 0, true},
+		{"month", 0, true},
+		{"", 0, true},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			got, err := parseRecommendationAge(test.value)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parseRecommendationAge(%q) error = %v, wantErr %t", test.value, err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("parseRecommendationAge(%q) = %s, want %s", test.value, got, test.want)
+			}
+		})
+	}
+}
+
+func TestStalenessPolicyApply(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	fresh := now.Add(-24 * time.Hour).UnixMilli()
+	stale := now.Add(-60 * 24 * time.Hour).UnixMilli()
+	tests := []struct {
+		name         string
+		policy       *stalenessPolicy
+		epochMillis  int64
+		wantFallback bool
+		wantWarning  bool
+		wantError    bool
+	}{
+		{"no policy", nil, stale, false, false, false},
+		{"fresh", &stalenessPolicy{maxAge: 30 * 24 * time.Hour, onStale: onStaleFallback}, fresh, false, false, false},
+		{"stale fallback", &stalenessPolicy{maxAge: 30 * 24 * time.Hour, onStale: onStaleFallback}, stale, true, true, false},
+		{"stale warn", &stalenessPolicy{maxAge: 30 * 24 * time.Hour, onStale: onStaleWarn}, stale, false, true, false},
+		{"stale error", &stalenessPolicy{maxAge: 30 * 24 * time.Hour, onStale: onStaleError}, stale, false, false, true},
+		{"no timestamp", &stalenessPolicy{maxAge: 30 * 24 * time.Hour, onStale: onStaleFallback}, 0, true, true, false},
+		{"no timestamp error", &stalenessPolicy{maxAge: 30 * 24 * time.Hour, onStale: onStaleError}, 0, false, false, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			fallback := test.policy.Apply(&diags, test.epochMillis, now)
+			if fallback != test.wantFallback {
+				t.Errorf("Apply() = %t, want %t", fallback, test.wantFallback)
+			}
+			if got := diags.WarningsCount() > 0; got != test.wantWarning {
+				t.Errorf("Apply() warnings = %v, want warning %t", diags, test.wantWarning)
+			}
+			if got := diags.HasError(); got != test.wantError {
+				t.Errorf("Apply() errors = %v, want error %t", diags, test.wantError)
+			}
+		})
+	}
+}
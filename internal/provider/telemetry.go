@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/joelpereira/densify-api-client-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the OpenTelemetry instrumentation name of the provider.
+const tracerName = "terraform-provider-densify"
+
+// Sources of a data source result, recorded on its span.
+const (
+	resultAPI      = "api"
+	resultFile     = "file"
+	resultCache    = "cache"
+	resultFallback = "fallback"
+)
+
+// parentSpan is the remote span the provider spans belong to, read from the TRACEPARENT environment variable
+// so that CI pipelines can join the provider spans to the trace of their Terraform run.
+var parentSpan trace.SpanContext
+
+// InitTelemetry exports the provider spans with OTLP over HTTP when OTEL_EXPORTER_OTLP_ENDPOINT (or
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT) is set; the spans are no-ops otherwise. The exporter is configured with the
+// standard OTEL_EXPORTER_OTLP_* environment variables. The returned function flushes the spans on exit.
+func InitTelemetry(ctx context.Context, version string) (func(context.Context) error, error) {
+	parentSpan = trace.SpanContextFromContext(propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{
+		"traceparent": os.Getenv("TRACEPARENT"),
+		"tracestate":  os.Getenv("TRACESTATE"),
+	}))
+
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(tracerName),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, err
+	}
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	return tracerProvider.Shutdown, nil
+}
+
+// startSpan starts a provider span. Spans without a parent in ctx belong to the TRACEPARENT span, if any.
+func startSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() && parentSpan.IsValid() {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, parentSpan)
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// endSpan ends the span of a provider operation, with an error status when the operation failed.
+func endSpan(span trace.Span, diags diag.Diagnostics) {
+	if errs := diags.Errors(); len(errs) > 0 {
+		span.SetStatus(codes.Error, errs[0].Summary())
+	}
+	span.End()
+}
+
+// recordQuery adds the technology platform and account (or cluster) of a query to the span of ctx.
+func recordQuery(ctx context.Context, query *densify.DensifyAPIQuery) {
+	account := query.AccountNumber
+	if account == "" {
+		account = query.AccountName
+	}
+	if query.AnalysisTechnology == k8sPlatform {
+		account = query.K8sCluster
+	}
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("densify.tech_platform", query.AnalysisTechnology),
+		attribute.String("densify.account", account),
+	)
+}
+
+// sourceResult returns the result source of the reads of a recommendation source, told apart by its name: file
+// for a recommendations file, api for the Densify API.
+func sourceResult(source RecommendationSource) string {
+	if strings.HasPrefix(source.Name(), fileSourcePrefix) {
+		return resultFile
+	}
+	return resultAPI
+}
+
+// recordResult adds the source of a data source result (api, file, cache or fallback) to the span of ctx.
+func recordResult(ctx context.Context, source string) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("densify.result_source", source))
+}
@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// densifyAPISubsystem is the tflog subsystem of the Densify API calls. Its level follows TF_LOG, or
+// TF_LOG_PROVIDER_DENSIFY_DENSIFY_API to trace the API calls only.
+const densifyAPISubsystem = "densify_api"
+
+// densifyAPILogLevel is the environment variable setting the level of the densify_api subsystem. It defaults
+// to the level of the provider logs (TF_LOG_PROVIDER_DENSIFY, then TRACE).
+const densifyAPILogLevel = "TF_LOG_PROVIDER_DENSIFY_DENSIFY_API"
+
+// maxTracedBodyBytes limits the size of the response body logged for each Densify API call.
+const maxTracedBodyBytes = 8192
+
+// redactedBodyFields matches the JSON fields of the Densify API bodies that hold credentials.
+var redactedBodyFields = regexp.MustCompile(`(?i)("[a-z_]*(?:token|password|pwd|secret|authorization)"\s*:\s*)"[^"]*"`)
+
+// tracingTransport logs the method, URL, status, latency and redacted response body of every Densify API
+// call in the densify_api subsystem, at trace level, and records a span for it. The response bodies are only
+// read and redacted when the trace logs are kept.
+type tracingTransport struct {
+	ctx       context.Context
+	next      http.RoundTripper
+	traceBody bool
+}
+
+// newTracingTransport wraps the transport of the Densify API client. The calls are logged and traced with ctx.
+func newTracingTransport(ctx context.Context, next http.RoundTripper) *tracingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &tracingTransport{
+		ctx:       tflog.NewSubsystem(ctx, densifyAPISubsystem, tflog.WithLevelFromEnv(densifyAPILogLevel)),
+		next:      next,
+		traceBody: apiTraceEnabled(),
+	}
+}
+
+// apiTraceEnabled reports whether the densify_api trace logs are kept. Terraform only keeps the provider logs
+// at the TF_LOG_PROVIDER (or TF_LOG) level, and the subsystem may log at a lower level than the provider.
+func apiTraceEnabled() bool {
+	terraformLevel := os.Getenv("TF_LOG_PROVIDER")
+	if terraformLevel == "" {
+		terraformLevel = os.Getenv("TF_LOG")
+	}
+	if terraformLevel == "" || !isTraceLevel(terraformLevel) {
+		return false
+	}
+	level := os.Getenv(densifyAPILogLevel)
+	if level == "" {
+		level = os.Getenv("TF_LOG_PROVIDER_DENSIFY")
+	}
+	return level == "" || isTraceLevel(level)
+}
+
+// isTraceLevel reports whether a TF_LOG level logs at trace level. Like Terraform, unknown levels (ex. JSON)
+// mean trace.
+func isTraceLevel(level string) bool {
+	switch strings.ToLower(level) {
+	case "debug", "info", "warn", "error", "off":
+		return false
+	}
+	return true
+}
+
+// RoundTrip calls the Densify API and logs the call.
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	fields := map[string]any{
+		"method": req.Method,
+		"url":    req.URL.Redacted(),
+	}
+	_, span := startSpan(t.ctx, "densify_api "+req.Method+" "+req.URL.Path,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.URLFull(req.URL.Redacted()),
+		),
+	)
+	defer span.End()
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		fields["latency_ms"] = time.Since(start).Milliseconds()
+		fields["error"] = err.Error()
+		tflog.SubsystemTrace(t.ctx, densifyAPISubsystem, "Densify API call failed", fields)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+
+	fields["latency_ms"] = time.Since(start).Milliseconds()
+	fields["status"] = resp.StatusCode
+	if t.traceBody {
+		// read the body so it can be logged, then hand a copy back to the client.
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			fields["error"] = err.Error()
+			tflog.SubsystemTrace(t.ctx, densifyAPISubsystem, "Densify API call failed", fields)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		fields["response_bytes"] = len(body)
+		fields["response_body"] = redactBody(body)
+	}
+	tflog.SubsystemTrace(t.ctx, densifyAPISubsystem, "Densify API call", fields)
+	return resp, nil
+}
+
+// redactBody masks the credentials of a Densify API body and truncates it to maxTracedBodyBytes. The whole body
+// is redacted first, since a credential cut by the truncation would no longer match redactedBodyFields.
+func redactBody(body []byte) string {
+	redacted := redactedBodyFields.ReplaceAllString(string(body), `$1"***"`)
+	if len(redacted) > maxTracedBodyBytes {
+		return redacted[:maxTracedBodyBytes] + "...(truncated)"
+	}
+	return redacted
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"no credentials", `{"name":"web-01","type":"m5.large"}`, `{"name":"web-01","type":"m5.large"}`},
+		{"token", `{"apiToken": "abc.def", "expires": 300}`, `{"apiToken": "***", "expires": 300}`},
+		{"password", `{"userName":"svc","pwd":"s3cret","PASSWORD":"x"}`, `{"userName":"svc","pwd":"***","PASSWORD":"***"}`},
+		{"authorization", `{"authorization":"Bearer abc","client_secret":"xyz"}`, `{"authorization":"***","client_secret":"***"}`},
+		{"truncated", strings.Repeat("a", maxTracedBodyBytes+10), strings.Repeat("a", maxTracedBodyBytes) + "...(truncated)"},
+		{
+			"credential cut by the truncation",
+			`{"name":"` + strings.Repeat("a", maxTracedBodyBytes-30) + `","apiToken":"SECRETSECRETSECRETSECRET","type":"m5.large"}`,
+			`{"name":"` + strings.Repeat("a", maxTracedBodyBytes-30) + `","apiToken":"***","t...(truncated)`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := redactBody([]byte(test.body)); got != test.want {
+				t.Errorf("redactBody() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAPITraceEnabled(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"no logs", map[string]string{}, false},
+		{"TF_LOG trace", map[string]string{"TF_LOG": "TRACE"}, true},
+		{"TF_LOG json", map[string]string{"TF_LOG": "JSON"}, true},
+		{"TF_LOG debug", map[string]string{"TF_LOG": "DEBUG"}, false},
+		{"TF_LOG_PROVIDER trace", map[string]string{"TF_LOG": "INFO", "TF_LOG_PROVIDER": "trace"}, true},
+		{"TF_LOG_PROVIDER debug", map[string]string{"TF_LOG": "TRACE", "TF_LOG_PROVIDER": "DEBUG"}, false},
+		{"provider debug", map[string]string{"TF_LOG": "TRACE", "TF_LOG_PROVIDER_DENSIFY": "DEBUG"}, false},
+		{"subsystem debug", map[string]string{"TF_LOG": "TRACE", densifyAPILogLevel: "DEBUG"}, false},
+		{"subsystem trace", map[string]string{"TF_LOG": "TRACE", "TF_LOG_PROVIDER_DENSIFY": "DEBUG", densifyAPILogLevel: "TRACE"}, true},
+		{"subsystem without terraform logs", map[string]string{densifyAPILogLevel: "TRACE"}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, name := range []string{"TF_LOG", "TF_LOG_PROVIDER", "TF_LOG_PROVIDER_DENSIFY", densifyAPILogLevel} {
+				t.Setenv(name, test.env[name])
+			}
+			if got := apiTraceEnabled(); got != test.want {
+				t.Errorf("apiTraceEnabled() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+// countingBody counts the reads of a response body.
+type countingBody struct {
+	io.Reader
+	reads int
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	b.reads++
+	return b.Reader.Read(p)
+}
+
+func (b *countingBody) Close() error { return nil }
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestTracingTransportBody(t *testing.T) {
+	for _, traceBody := range []bool{false, true} {
+		body := &countingBody{Reader: strings.NewReader(`{"apiToken":"abc"}`)}
+		transport := newTracingTransport(context.Background(), roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: body}, nil
+		}))
+		transport.traceBody = traceBody
+
+		req, err := http.NewRequest(http.MethodGet, "https://densify.example.com/systems", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := body.reads > 0; got != traceBody {
+			t.Errorf("traceBody %t: body read by the transport = %t", traceBody, got)
+		}
+		got, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != `{"apiToken":"abc"}` {
+			t.Errorf("traceBody %t: response body = %q, want the unredacted body", traceBody, got)
+		}
+	}
+}
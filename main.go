@@ -7,6 +7,7 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 
@@ -32,6 +33,14 @@ var (
 )
 
 func main() {
+	// "terraform-provider-densify export ..." dumps recommendations instead of serving the plugin.
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := provider.Export(context.Background(), os.Args[2:], os.Stdout); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	var debug bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
@@ -47,7 +56,17 @@ func main() {
 		Debug:   debug,
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	ctx := context.Background()
+	shutdownTelemetry, err := provider.InitTelemetry(ctx, version)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	err = providerserver.Serve(ctx, provider.New(version), opts)
+	// flush the spans before exiting, including on errors.
+	if shutdownErr := shutdownTelemetry(ctx); shutdownErr != nil {
+		log.Print(shutdownErr.Error())
+	}
 
 	if err != nil {
 		log.Fatal(err.Error())